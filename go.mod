@@ -3,27 +3,15 @@ module github.com/rickardl/terraform-artifactory-provider
 replace github.com/rickardl/go-artifactory/v2 => /Users/rickardlofstrom/rickardl/go-artifactory
 
 require (
-	cloud.google.com/go v0.51.0 // indirect
-	cloud.google.com/go/storage v1.5.0 // indirect
 	github.com/aws/aws-sdk-go v1.28.0 // indirect
-	github.com/bmatcuk/doublestar v1.2.2 // indirect
 	github.com/fatih/color v1.9.0 // indirect
-	github.com/google/go-cmp v0.4.0 // indirect
-	github.com/hashicorp/go-hclog v0.10.0 // indirect
-	github.com/hashicorp/terraform v0.12.19
-	github.com/hashicorp/terraform-plugin-sdk v1.4.1
+	github.com/hashicorp/go-cty v1.4.1-0.20200414143053-d3edf31b6320
+	github.com/hashicorp/hcl/v2 v2.3.0
+	github.com/hashicorp/terraform-plugin-sdk/v2 v2.10.1
 	github.com/oklog/run v1.1.0 // indirect
-	github.com/posener/complete v1.2.3 // indirect
 	github.com/rickardl/go-artifactory/v2 v2.5.9
-	github.com/stretchr/testify v1.4.0
-	github.com/ulikunitz/xz v0.5.6 // indirect
-	github.com/vmihailenco/msgpack v4.0.4+incompatible // indirect
-	github.com/zclconf/go-cty v1.2.1 // indirect
-	golang.org/x/crypto v0.0.0-20200109152110-61a87790db17 // indirect
-	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d // indirect
-	golang.org/x/sys v0.0.0-20200107162124-548cf772de50 // indirect
-	golang.org/x/tools v0.0.0-20200110213125-a7a6caa82ab2 // indirect
-	google.golang.org/genproto v0.0.0-20200108215221-bd8f9a0ef82f // indirect
+	github.com/stretchr/testify v1.7.0
+	github.com/zclconf/go-cty v1.9.1
 )
 
 go 1.13
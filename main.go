@@ -1,13 +1,42 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform/plugin"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 
 	"github.com/rickardl/terraform-artifactory-provider/pkg/artifactory"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		generate(os.Args[2:])
+		return
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: artifactory.Provider,
 	})
 }
+
+// generate exports the configuration of an existing artifactory instance as terraform files
+func generate(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	dir := flags.String("dir", ".", "directory to write the .tf files and imports to")
+	importStyle := flags.String("import-style", artifactory.ImportBlocks,
+		"write the imports as import blocks to imports.tf (blocks, terraform 1.5+) or as commands to import.sh (script)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s generate [-dir DIR] [-import-style blocks|script]\n\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "Connects with ARTIFACTORY_URL and the ARTIFACTORY_* credentials the provider uses.")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if err := artifactory.Generate(context.Background(), *dir, *importStyle); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
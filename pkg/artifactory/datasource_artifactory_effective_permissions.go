@@ -0,0 +1,333 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceArtifactoryEffectivePermissions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceEffectivePermissionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+			"group": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+			"repositories": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"repository": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"permissions": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+						"grant": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"permission_target": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"section": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"group": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"permissions": {
+										Type:     schema.TypeSet,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+										Set:      schema.HashString,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// entitySection is a section of a permission target as seen by a single user or group
+type entitySection struct {
+	Repositories []string `json:"repositories"`
+	Actions      []string `json:"actions"`
+}
+
+// entityPermissions is the answer of GET /api/v2/security/permissions/{users|groups}/{name}, keyed by permission
+// target and section
+type entityPermissions struct {
+	Name        string                              `json:"name"`
+	Permissions map[string]map[string]entitySection `json:"permissions"`
+}
+
+// v1 permission letters, m=admin; d=delete; w=deploy; n=annotate; r=read
+var permissionsFromV1 = map[string]string{"m": "manage", "d": "delete", "w": "write", "n": "annotate", "r": "read"}
+
+// permissionSections maps the sections of the permissions api to the names used by the provider
+var permissionSections = map[string]string{"repo": "repo", "build": "build", "releaseBundle": "release_bundle"}
+
+// effectivePrincipal is a principal whose permission targets apply, group is set when it is reached through
+// group membership
+type effectivePrincipal struct {
+	entityType string
+	name       string
+	group      string
+}
+
+type effectiveGrant struct {
+	repository       string
+	permissionTarget string
+	section          string
+	group            string
+	permissions      []string
+}
+
+type effectivePermissions struct {
+	c     *ArtClient
+	repos map[string]repositorySummary
+	v1    map[string]*entityPermissions
+}
+
+func (e *effectivePermissions) entity(ctx context.Context, p effectivePrincipal) (*entityPermissions, error) {
+	req, err := e.c.Raw.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/security/permissions/%s/%s", p.entityType, p.name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := new(entityPermissions)
+	resp, err := e.c.Raw.Do(ctx, req, entity)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return e.entityV1(ctx, p)
+	}
+	return entity, err
+}
+
+// entityV1 answers per-entity queries for artifactory versions before 6.6.0 from the v1 permission targets, which
+// only have a repo section
+func (e *effectivePermissions) entityV1(ctx context.Context, p effectivePrincipal) (*entityPermissions, error) {
+	if e.v1 == nil {
+		e.v1 = map[string]*entityPermissions{}
+
+		targets, _, err := e.c.V1.Security.ListPermissionTargets(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list permission targets: %s", err)
+		}
+		for _, t := range targets {
+			target, _, err := e.c.V1.Security.GetPermissionTargets(ctx, *t.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read permission target %s: %s", *t.Name, err)
+			}
+			if target.Principals == nil || target.Repositories == nil {
+				continue
+			}
+
+			for entityType, principals := range map[string]*map[string][]string{"users": target.Principals.Users, "groups": target.Principals.Groups} {
+				if principals == nil {
+					continue
+				}
+				for name, letters := range *principals {
+					var actions []string
+					for _, l := range letters {
+						actions = append(actions, permissionsFromV1[l])
+					}
+
+					key := entityType + "/" + name
+					if e.v1[key] == nil {
+						e.v1[key] = &entityPermissions{Name: name, Permissions: map[string]map[string]entitySection{}}
+					}
+					e.v1[key].Permissions[*t.Name] = map[string]entitySection{
+						"repo": {Repositories: *target.Repositories, Actions: actions},
+					}
+				}
+			}
+		}
+	}
+
+	if entity, ok := e.v1[p.entityType+"/"+p.name]; ok {
+		return entity, nil
+	}
+	return &entityPermissions{Name: p.name}, nil
+}
+
+// permissionSectionName returns the provider name of a section, sections it doesn't know keep the api name
+func permissionSectionName(section string) string {
+	if name, ok := permissionSections[section]; ok {
+		return name
+	}
+	return section
+}
+
+// expand replaces the special repository keywords with the repositories they stand for
+func (e *effectivePermissions) expand(keys []string) []string {
+	var expanded []string
+	for _, key := range keys {
+		if !containsString(specialRepositories, key) {
+			expanded = append(expanded, key)
+			continue
+		}
+
+		for _, repo := range e.repos {
+			t := strings.ToLower(repo.Type)
+			switch {
+			case key == "ANY" && t != "virtual",
+				key == "ANY LOCAL" && (t == "local" || t == "federated"),
+				key == "ANY REMOTE" && t == "remote",
+				key == "ANY DISTRIBUTION" && t == "distribution":
+				expanded = append(expanded, repo.Key)
+			}
+		}
+	}
+	return expanded
+}
+
+func (e *effectivePermissions) grants(ctx context.Context, principals []effectivePrincipal) ([]effectiveGrant, error) {
+	var grants []effectiveGrant
+	for _, p := range principals {
+		entity, err := e.entity(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read permissions of %s %s: %s", strings.TrimSuffix(p.entityType, "s"), p.name, err)
+		}
+
+		for target, sections := range entity.Permissions {
+			for section, s := range sections {
+				if len(s.Actions) == 0 {
+					continue
+				}
+				for _, repo := range e.expand(s.Repositories) {
+					grants = append(grants, effectiveGrant{
+						repository:       repo,
+						permissionTarget: target,
+						section:          permissionSectionName(section),
+						group:            p.group,
+						permissions:      s.Actions,
+					})
+				}
+			}
+		}
+	}
+	return grants, nil
+}
+
+// packEffectivePermissions groups the grants by repository, keeping only the filtered repositories if any are given
+func packEffectivePermissions(grants []effectiveGrant, filter []string) []interface{} {
+	byRepo := map[string][]effectiveGrant{}
+	for _, g := range grants {
+		if len(filter) == 0 || containsString(filter, g.repository) {
+			byRepo[g.repository] = append(byRepo[g.repository], g)
+		}
+	}
+
+	keys := make([]string, 0, len(byRepo))
+	for key := range byRepo {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var repos []interface{}
+	for _, key := range keys {
+		grants := byRepo[key]
+		sort.Slice(grants, func(i, j int) bool {
+			a, b := grants[i], grants[j]
+			if a.permissionTarget != b.permissionTarget {
+				return a.permissionTarget < b.permissionTarget
+			}
+			if a.section != b.section {
+				return a.section < b.section
+			}
+			return a.group < b.group
+		})
+
+		permissions := schema.NewSet(schema.HashString, nil)
+		var packed []interface{}
+		for _, g := range grants {
+			for _, p := range g.permissions {
+				permissions.Add(p)
+			}
+			packed = append(packed, map[string]interface{}{
+				"permission_target": g.permissionTarget,
+				"section":           g.section,
+				"group":             g.group,
+				"permissions":       schema.NewSet(schema.HashString, castToInterfaceArr(g.permissions)),
+			})
+		}
+
+		repos = append(repos, map[string]interface{}{
+			"key":         key,
+			"permissions": permissions,
+			"grant":       packed,
+		})
+	}
+	return repos
+}
+
+func dataSourceEffectivePermissionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	var principals []effectivePrincipal
+	if group, ok := d.GetOk("group"); ok {
+		principals = append(principals, effectivePrincipal{entityType: "groups", name: group.(string), group: group.(string)})
+	} else {
+		name := d.Get("user").(string)
+		principals = append(principals, effectivePrincipal{entityType: "users", name: name})
+
+		user, _, err := c.V1.Security.GetUser(ctx, name)
+		if err != nil {
+			return diag.Errorf("failed to read user %s: %s", name, err)
+		}
+		if user.Groups != nil {
+			for _, group := range *user.Groups {
+				principals = append(principals, effectivePrincipal{entityType: "groups", name: group, group: group})
+			}
+		}
+	}
+
+	repos, err := listRepositories(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	e := &effectivePermissions{c: c, repos: repos}
+	grants, err := e.grants(ctx, principals)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(principals[0].entityType + ":" + principals[0].name)
+
+	p := newPacker("artifactory_effective_permissions", d)
+	p.set("repository", packEffectivePermissions(grants, castToStringArr(d.Get("repositories").(*schema.Set).List())))
+	return diag.FromErr(p.err())
+}
@@ -0,0 +1,95 @@
+package artifactory
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const effectivePermissionsConfig = `
+resource "artifactory_local_repository" "lib-local" {
+  key          = "terraform-effective-permissions"
+  package_type = "generic"
+}
+
+resource "artifactory_permission_target" "test-perm" {
+  name = "test-perm-effective"
+
+  repo {
+    repositories = [artifactory_local_repository.lib-local.key]
+
+    actions {
+      groups {
+        name        = "readers"
+        permissions = ["read"]
+      }
+    }
+  }
+}
+
+data "artifactory_effective_permissions" "readers" {
+  group        = "readers"
+  repositories = [artifactory_local_repository.lib-local.key]
+
+  depends_on = [artifactory_permission_target.test-perm]
+}`
+
+func TestAccDataEffectivePermissions(t *testing.T) {
+	const id = "data.artifactory_effective_permissions.readers"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: effectivePermissionsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "repository.#", "1"),
+					resource.TestCheckResourceAttr(id, "repository.0.key", "terraform-effective-permissions"),
+					resource.TestCheckResourceAttr(id, "repository.0.grant.0.permission_target", "test-perm-effective"),
+					resource.TestCheckResourceAttr(id, "repository.0.grant.0.group", "readers"),
+				),
+			},
+		},
+	})
+}
+
+func TestEffectivePermissions_expand(t *testing.T) {
+	e := &effectivePermissions{repos: map[string]repositorySummary{
+		"libs-local":   {Key: "libs-local", Type: "LOCAL"},
+		"libs-remote":  {Key: "libs-remote", Type: "REMOTE"},
+		"libs-virtual": {Key: "libs-virtual", Type: "VIRTUAL"},
+	}}
+
+	assert.Equal(t, []string{"libs-local", "other"}, e.expand([]string{"ANY LOCAL", "other"}))
+	assert.ElementsMatch(t, []string{"libs-local", "libs-remote"}, e.expand([]string{"ANY"}))
+	assert.Empty(t, e.expand([]string{"ANY DISTRIBUTION"}))
+}
+
+func TestPackEffectivePermissions(t *testing.T) {
+	repos := packEffectivePermissions([]effectiveGrant{
+		{repository: "libs-remote", permissionTarget: "readers", section: "repo", group: "readers", permissions: []string{"read"}},
+		{repository: "libs-local", permissionTarget: "deployers", section: "repo", permissions: []string{"read", "write"}},
+		{repository: "libs-local", permissionTarget: "annotators", section: "repo", group: "qa", permissions: []string{"annotate"}},
+	}, []string{"libs-local"})
+
+	assert.Len(t, repos, 1)
+	repo := repos[0].(map[string]interface{})
+	assert.Equal(t, "libs-local", repo["key"])
+	assert.ElementsMatch(t, []interface{}{"read", "write", "annotate"}, repo["permissions"].(*schema.Set).List())
+
+	grants := repo["grant"].([]interface{})
+	assert.Len(t, grants, 2)
+	assert.Equal(t, "annotators", grants[0].(map[string]interface{})["permission_target"])
+	assert.Equal(t, "qa", grants[0].(map[string]interface{})["group"])
+	assert.Equal(t, "deployers", grants[1].(map[string]interface{})["permission_target"])
+	assert.Equal(t, "", grants[1].(map[string]interface{})["group"])
+}
+
+func TestPermissionSectionName(t *testing.T) {
+	assert.Equal(t, "repo", permissionSectionName("repo"))
+	assert.Equal(t, "release_bundle", permissionSectionName("releaseBundle"))
+	assert.Equal(t, "other", permissionSectionName("other"))
+}
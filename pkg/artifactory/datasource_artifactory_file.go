@@ -4,16 +4,15 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"io"
 	"os"
 )
 
 func dataSourceArtifactoryFile() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceFileRead,
+		ReadContext: dataSourceFileRead,
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -81,17 +80,17 @@ func dataSourceArtifactoryFile() *schema.Resource {
 	}
 }
 
-func dataSourceFileRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func dataSourceFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	repository := d.Get("repository").(string)
 	path := d.Get("path").(string)
 	outputPath := d.Get("output_path").(string)
 	forceOverwrite := d.Get("force_overwrite").(bool)
 
-	fileInfo, _, err := c.V1.Artifacts.FileInfo(context.Background(), repository, path)
+	fileInfo, _, err := c.V1.Artifacts.FileInfo(ctx, repository, path)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	fileExists := FileExists(outputPath)
@@ -100,20 +99,20 @@ func dataSourceFileRead(d *schema.ResourceData, m interface{}) error {
 	if !fileExists || (!chksMatches && forceOverwrite) {
 		outFile, err := os.Create(outputPath)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		defer outFile.Close()
 
-		fileInfo, _, err = c.V1.Artifacts.FileContents(context.Background(), repository, path, outFile)
+		fileInfo, _, err = c.V1.Artifacts.FileContents(ctx, repository, path, outFile)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else if !chksMatches {
-		return fmt.Errorf("Local file differs from upstream version")
+		return diag.Errorf("Local file differs from upstream version")
 	}
 
-	return packFileInfo(fileInfo, d)
+	return diag.FromErr(packFileInfo(fileInfo, d))
 }
 
 func FileExists(path string) bool {
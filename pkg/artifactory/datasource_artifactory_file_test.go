@@ -1,4 +1,4 @@
-package artifactory_test
+package artifactory
 
 import (
 	"github.com/stretchr/testify/assert"
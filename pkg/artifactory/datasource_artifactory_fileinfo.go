@@ -2,15 +2,14 @@ package artifactory
 
 import (
 	"context"
-	"fmt"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rickardl/go-artifactory/v2/artifactory/v1"
-	"github.com/hashicorp/terraform/helper/schema"
 )
 
 func dataSourceArtifactoryFileInfo() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceFileInfoRead,
+		ReadContext: dataSourceFileInfoRead,
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -69,44 +68,39 @@ func dataSourceArtifactoryFileInfo() *schema.Resource {
 	}
 }
 
-func dataSourceFileInfoRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func dataSourceFileInfoRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	repository := d.Get("repository").(string)
 	path := d.Get("path").(string)
 
-	fileInfo, _, err := c.V1.Artifacts.FileInfo(context.Background(), repository, path)
+	fileInfo, _, err := c.V1.Artifacts.FileInfo(ctx, repository, path)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return packFileInfo(fileInfo, d)
+	return diag.FromErr(packFileInfo(fileInfo, d))
 }
 
 func packFileInfo(fileInfo *v1.FileInfo, d *schema.ResourceData) error {
-	hasErr := false
-	logErr := cascadingErr(&hasErr)
+	p := newPacker("artifactory_fileinfo", d)
 
 	d.SetId(*fileInfo.DownloadUri)
 
-	logErr(d.Set("created", *fileInfo.Created))
-	logErr(d.Set("created_by", *fileInfo.CreatedBy))
-	logErr(d.Set("last_modified", *fileInfo.LastModified))
-	logErr(d.Set("modified_by", *fileInfo.ModifiedBy))
-	logErr(d.Set("last_updated", *fileInfo.LastUpdated))
-	logErr(d.Set("download_uri", *fileInfo.DownloadUri))
-	logErr(d.Set("mimetype", *fileInfo.MimeType))
-	logErr(d.Set("size", *fileInfo.Size))
+	p.set("created", *fileInfo.Created)
+	p.set("created_by", *fileInfo.CreatedBy)
+	p.set("last_modified", *fileInfo.LastModified)
+	p.set("modified_by", *fileInfo.ModifiedBy)
+	p.set("last_updated", *fileInfo.LastUpdated)
+	p.set("download_uri", *fileInfo.DownloadUri)
+	p.set("mimetype", *fileInfo.MimeType)
+	p.set("size", *fileInfo.Size)
 
 	if fileInfo.Checksums != nil {
-		logErr(d.Set("md5", *fileInfo.Checksums.Md5))
-		logErr(d.Set("sha1", *fileInfo.Checksums.Sha1))
-		logErr(d.Set("sha256", *fileInfo.Checksums.Sha256))
+		p.set("md5", *fileInfo.Checksums.Md5)
+		p.set("sha1", *fileInfo.Checksums.Sha1)
+		p.set("sha256", *fileInfo.Checksums.Sha256)
 	}
 
-	if hasErr {
-		return fmt.Errorf("failed to pack fileInfo")
-	}
-
-	return nil
+	return p.err()
 }
@@ -1,16 +1,15 @@
 package artifactory
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceArtifactoryGroup() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceGroupRead,
-		Exists: resourceGroupExists,
+		ReadContext: resourceGroupRead,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
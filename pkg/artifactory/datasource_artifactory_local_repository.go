@@ -2,17 +2,16 @@ package artifactory
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceArtifactoryLocalRepository() *schema.Resource {
 	return &schema.Resource{
-		Read: dataLocalRepositoryRead,
+		ReadContext: dataLocalRepositoryRead,
 
 		Schema: map[string]*schema.Schema{
 			"key": {
@@ -129,48 +128,47 @@ func dataSourceArtifactoryLocalRepository() *schema.Resource {
 	}
 }
 
-func dataLocalRepositoryRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func dataLocalRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	key := d.Get("key").(string)
 	log.Printf("[DEBUG] Reading Local Repository with Key: %s", key)
-	repo, resp, err := c.V1.Repositories.GetLocal(context.Background(), key)
+	repo, resp, err := c.V1.Repositories.GetLocal(ctx, key)
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 	} else if err == nil {
-		hasErr := false
-		logError := cascadingErr(&hasErr)
-		logError(d.Set("key", repo.Key))
-		logError(d.Set("package_type", repo.PackageType))
-		logError(d.Set("description", repo.Description))
-		logError(d.Set("notes", repo.Notes))
-		logError(d.Set("includes_pattern", repo.IncludesPattern))
-		logError(d.Set("excludes_pattern", repo.ExcludesPattern))
-		logError(d.Set("repo_layout_ref", repo.RepoLayoutRef))
-		logError(d.Set("debian_trivial_layout", repo.DebianTrivialLayout))
-		logError(d.Set("max_unique_tags", repo.MaxUniqueTags))
-		logError(d.Set("blacked_out", repo.BlackedOut))
-		logError(d.Set("archive_browsing_enabled", repo.ArchiveBrowsingEnabled))
-		logError(d.Set("calculate_yum_metadata", repo.CalculateYumMetadata))
-		logError(d.Set("yum_root_depth", repo.YumRootDepth))
-		logError(d.Set("docker_api_version", repo.DockerApiVersion))
-		logError(d.Set("enable_file_lists_indexing", repo.EnableFileListsIndexing))
-		logError(d.Set("property_sets", schema.NewSet(schema.HashString, castToInterfaceArr(*repo.PropertySets))))
-		logError(d.Set("handle_releases", repo.HandleReleases))
-		logError(d.Set("handle_snapshots", repo.HandleSnapshots))
-		logError(d.Set("checksum_policy_type", repo.ChecksumPolicyType))
-		logError(d.Set("max_unique_snapshots", repo.MaxUniqueSnapshots))
-		logError(d.Set("snapshot_version_behavior", repo.SnapshotVersionBehavior))
-		logError(d.Set("suppress_pom_consistency_checks", repo.SuppressPomConsistencyChecks))
-		logError(d.Set("xray_index", repo.XrayIndex))
+		p := newPacker("artifactory_local_repository", d)
+		p.set("key", repo.Key)
+		p.set("package_type", repo.PackageType)
+		p.set("description", repo.Description)
+		p.set("notes", repo.Notes)
+		p.set("includes_pattern", repo.IncludesPattern)
+		p.set("excludes_pattern", repo.ExcludesPattern)
+		p.set("repo_layout_ref", repo.RepoLayoutRef)
+		p.set("debian_trivial_layout", repo.DebianTrivialLayout)
+		p.set("max_unique_tags", repo.MaxUniqueTags)
+		p.set("blacked_out", repo.BlackedOut)
+		p.set("archive_browsing_enabled", repo.ArchiveBrowsingEnabled)
+		p.set("calculate_yum_metadata", repo.CalculateYumMetadata)
+		p.set("yum_root_depth", repo.YumRootDepth)
+		p.set("docker_api_version", repo.DockerApiVersion)
+		p.set("enable_file_lists_indexing", repo.EnableFileListsIndexing)
+		p.set("property_sets", schema.NewSet(schema.HashString, castToInterfaceArr(*repo.PropertySets)))
+		p.set("handle_releases", repo.HandleReleases)
+		p.set("handle_snapshots", repo.HandleSnapshots)
+		p.set("checksum_policy_type", repo.ChecksumPolicyType)
+		p.set("max_unique_snapshots", repo.MaxUniqueSnapshots)
+		p.set("snapshot_version_behavior", repo.SnapshotVersionBehavior)
+		p.set("suppress_pom_consistency_checks", repo.SuppressPomConsistencyChecks)
+		p.set("xray_index", repo.XrayIndex)
 
-		if hasErr {
-			return fmt.Errorf("failed to marshal group")
+		if err := p.err(); err != nil {
+			return diag.FromErr(err)
 		}
 		d.SetId(*repo.Key)
 
 	}
 
-	return err
+	return diag.FromErr(err)
 }
@@ -5,9 +5,9 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	v2 "github.com/rickardl/go-artifactory/v2/artifactory/v2"
 )
 
@@ -85,33 +85,34 @@ func dataSourceArtifactoryPermissionTarget() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Read: dataPermissionTargetRead,
+		ReadContext: dataPermissionTargetRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
-			"repo":  &principalSchema,
-			"build": &principalSchema,
+			"repo":           &principalSchema,
+			"build":          &principalSchema,
+			"release_bundle": &principalSchema,
 		},
 	}
 }
 
-func dataPermissionTargetRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func dataPermissionTargetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	name := d.Get("name").(string)
 	log.Printf("[DEBUG] Reading Perssmion Target with name: %s", name)
 
-	permissionTarget, resp, err := c.V2.Security.GetPermissionTarget(context.Background(), name)
-	if resp.StatusCode == http.StatusNotFound {
+	permissionTarget, resp, err := getPermissionTarget(ctx, c, name)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	} else if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*permissionTarget.Name)
-	return packPermissionTarget(permissionTarget, d)
+	return diag.FromErr(packPermissionTarget(permissionTarget, d))
 }
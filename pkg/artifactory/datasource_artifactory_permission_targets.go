@@ -0,0 +1,200 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v2 "github.com/rickardl/go-artifactory/v2/artifactory/v2"
+)
+
+// dataSourceArtifactoryPermissionTargets lists permission targets in the structure of artifactory_permission_target
+func dataSourceArtifactoryPermissionTargets() *schema.Resource {
+	computedStrings := func() *schema.Schema {
+		return &schema.Schema{
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Set:      schema.HashString,
+		}
+	}
+
+	actionSchema := &schema.Schema{
+		Type:     schema.TypeSet,
+		Computed: true,
+		Set:      hashPrincipal,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"permissions": computedStrings(),
+			},
+		},
+	}
+
+	sectionSchema := &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"includes_pattern": computedStrings(),
+				"excludes_pattern": computedStrings(),
+				"repositories":     computedStrings(),
+				"actions": {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"users":  actionSchema,
+							"groups": actionSchema,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourcePermissionTargetsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"user": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"group": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"permission_targets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"repo":           sectionSchema,
+						"build":          sectionSchema,
+						"release_bundle": sectionSchema,
+					},
+				},
+			},
+		},
+	}
+}
+
+// permissionTargetFilter keeps the permission targets matching all of its non empty fields
+type permissionTargetFilter struct {
+	name       *regexp.Regexp
+	repository string
+	user       string
+	group      string
+}
+
+func (f permissionTargetFilter) matches(permissionTarget *PermissionTarget) bool {
+	if f.name != nil && !f.name.MatchString(*permissionTarget.Name) {
+		return false
+	}
+
+	foundRepository, foundUser, foundGroup := f.repository == "", f.user == "", f.group == ""
+	for _, section := range []*v2.Permission{permissionTarget.Repo, permissionTarget.Build, permissionTarget.ReleaseBundle} {
+		if section == nil {
+			continue
+		}
+		if section.Repositories != nil && containsString(*section.Repositories, f.repository) {
+			foundRepository = true
+		}
+		if section.Actions == nil {
+			continue
+		}
+		if hasPrincipal(section.Actions.Users, f.user) {
+			foundUser = true
+		}
+		if hasPrincipal(section.Actions.Groups, f.group) {
+			foundGroup = true
+		}
+	}
+	return foundRepository && foundUser && foundGroup
+}
+
+func hasPrincipal(principals *map[string][]string, name string) bool {
+	if principals == nil {
+		return false
+	}
+	_, ok := (*principals)[name]
+	return ok
+}
+
+func packPermissionTargetSummary(permissionTarget *PermissionTarget) map[string]interface{} {
+	packed := map[string]interface{}{"name": *permissionTarget.Name}
+	for key, section := range map[string]*v2.Permission{
+		"repo":           permissionTarget.Repo,
+		"build":          permissionTarget.Build,
+		"release_bundle": permissionTarget.ReleaseBundle,
+	} {
+		if section != nil {
+			packed[key] = packPermission(section)
+		}
+	}
+	return packed
+}
+
+func dataSourcePermissionTargetsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	filter := permissionTargetFilter{
+		repository: d.Get("repository").(string),
+		user:       d.Get("user").(string),
+		group:      d.Get("group").(string),
+	}
+	if v, ok := d.GetOk("name_regex"); ok {
+		filter.name = regexp.MustCompile(v.(string))
+	}
+
+	targets, _, err := c.V1.Security.ListPermissionTargets(ctx)
+	if err != nil {
+		return diag.Errorf("failed to list permission targets: %s", err)
+	}
+
+	var names []string
+	for _, target := range targets {
+		if filter.name == nil || filter.name.MatchString(*target.Name) {
+			names = append(names, *target.Name)
+		}
+	}
+	sort.Strings(names)
+
+	var packed []interface{}
+	for _, name := range names {
+		permissionTarget, _, err := getPermissionTarget(ctx, c, name)
+		if err != nil {
+			return diag.Errorf("failed to read permission target %s: %s", name, err)
+		}
+		if filter.matches(permissionTarget) {
+			packed = append(packed, packPermissionTargetSummary(permissionTarget))
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s:%s", d.Get("name_regex"), filter.repository, filter.user, filter.group))
+
+	p := newPacker("artifactory_permission_targets", d)
+	p.set("permission_targets", packed)
+	return diag.FromErr(p.err())
+}
@@ -0,0 +1,69 @@
+package artifactory
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	v2 "github.com/rickardl/go-artifactory/v2/artifactory/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const permissionTargetsConfig = `
+resource "artifactory_permission_target" "test-perm" {
+  name = "test-perm-listed"
+
+  repo {
+    repositories = ["example-repo-local"]
+
+    actions {
+      groups {
+        name        = "readers"
+        permissions = ["read"]
+      }
+    }
+  }
+}
+
+data "artifactory_permission_targets" "readers" {
+  name_regex = "^test-perm-listed$"
+  group      = "readers"
+
+  depends_on = [artifactory_permission_target.test-perm]
+}`
+
+func TestAccDataPermissionTargets(t *testing.T) {
+	const id = "data.artifactory_permission_targets.readers"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: permissionTargetsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "permission_targets.#", "1"),
+					resource.TestCheckResourceAttr(id, "permission_targets.0.name", "test-perm-listed"),
+					resource.TestCheckResourceAttr(id, "permission_targets.0.repo.0.repositories.#", "1"),
+					resource.TestCheckResourceAttr(id, "permission_targets.0.repo.0.actions.0.groups.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestPermissionTargetFilter(t *testing.T) {
+	name, repositories := "deployers", []string{"libs-release-local"}
+	permissionTarget := new(PermissionTarget)
+	permissionTarget.Name = &name
+	permissionTarget.Repo = &v2.Permission{
+		Repositories: &repositories,
+		Actions:      &v2.Entity{Users: &map[string][]string{"jane": {"write"}}},
+	}
+
+	assert.True(t, permissionTargetFilter{}.matches(permissionTarget))
+	assert.True(t, permissionTargetFilter{name: regexp.MustCompile("^deploy"), repository: "libs-release-local", user: "jane"}.matches(permissionTarget))
+	assert.False(t, permissionTargetFilter{name: regexp.MustCompile("^read")}.matches(permissionTarget))
+	assert.False(t, permissionTargetFilter{repository: "libs-snapshot-local"}.matches(permissionTarget))
+	assert.False(t, permissionTargetFilter{group: "jane"}.matches(permissionTarget))
+}
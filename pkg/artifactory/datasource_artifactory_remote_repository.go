@@ -6,14 +6,14 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceArtifactoryRemoteRepository() *schema.Resource {
 	return &schema.Resource{
-		Read: dataRemoteRepositoryRead,
+		ReadContext: dataRemoteRepositoryRead,
 
 		Schema: map[string]*schema.Schema{
 			"key": {
@@ -77,12 +77,6 @@ func dataSourceArtifactoryRemoteRepository() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
-			"password": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Sensitive: true,
-				StateFunc: getMD5Hash,
-			},
 			"proxy": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -271,19 +265,19 @@ func dataSourceArtifactoryRemoteRepository() *schema.Resource {
 	}
 }
 
-func dataRemoteRepositoryRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func dataRemoteRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	key := d.Get("key").(string)
 	log.Printf("[DEBUG] Reading Local Repository with Key: %s", key)
 
-	repo, resp, err := c.V1.Repositories.GetRemote(context.Background(), key)
-	if resp.StatusCode == http.StatusNotFound {
+	repo, resp, err := c.V1.Repositories.GetRemote(ctx, key)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	} else if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(*repo.Key)
-	return packRemoteRepo(repo, d)
+	return diag.FromErr(packRemoteRepo(repo, d))
 }
@@ -4,74 +4,68 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceArtifactoryUser() *schema.Resource {
 	return &schema.Resource{
-		Read: dataUserRead,
+		ReadContext: dataUserRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"email": {
 				Type:     schema.TypeString,
-				Required: true,
+				Computed: true,
 			},
 			"admin": {
 				Type:     schema.TypeBool,
-				Optional: true,
 				Computed: true,
 			},
 			"profile_updatable": {
 				Type:     schema.TypeBool,
-				Optional: true,
 				Computed: true,
 			},
 			"disable_ui_access": {
 				Type:     schema.TypeBool,
-				Optional: true,
 				Computed: true,
 			},
 			"internal_password_disabled": {
 				Type:     schema.TypeBool,
-				Optional: true,
 				Computed: true,
 			},
 			"groups": {
 				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set:      schema.HashString,
-				Optional: true,
+				Computed: true,
+			},
+			"realm": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
-			"password": {
-				Type:      schema.TypeString,
-				Sensitive: true,
-				Optional:  true,
-				StateFunc: func(value interface{}) string {
-					// Avoid storing the actual value in the state and instead store the hash of it
-					return hashString(value.(string))
-				},
+			"last_logged_in": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
 }
 
-func dataUserRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func dataUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	user, resp, err := c.V1.Security.GetUser(context.Background(), d.Id())
-	if resp.StatusCode == http.StatusNotFound {
-		d.SetId("")
-		return nil
+	name := d.Get("name").(string)
+	user, resp, err := c.V1.Security.GetUser(ctx, name)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return diag.Errorf("user %s not found", name)
 	} else if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(*user.Name)
 
-	return packUser(user, d)
+	return diag.FromErr(packUser(user, d))
 }
@@ -0,0 +1,396 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// generatedResource is an object found on the server that Generate writes a resource and an import for
+type generatedResource struct {
+	resourceType string
+	id           string
+}
+
+// secretVariable is a variable standing in for a value that can't be read back from the server
+type secretVariable struct {
+	name        string
+	description string
+}
+
+// Import styles Generate writes the imports of the generated resources in
+const (
+	// ImportBlocks writes import blocks to imports.tf, they need terraform 1.5 or later
+	ImportBlocks = "blocks"
+	// ImportScript writes terraform import commands to import.sh
+	ImportScript = "script"
+)
+
+// Generate writes the configuration of every object on the artifactory server the provider supports into dir,
+// one .tf file per resource type, along with their imports in importStyle, ImportBlocks or ImportScript.
+// The connection is configured exactly like the provider, from the ARTIFACTORY_* environment variables
+func Generate(ctx context.Context, dir, importStyle string) error {
+	if importStyle != ImportBlocks && importStyle != ImportScript {
+		return fmt.Errorf("unknown import style %q, use %s or %s", importStyle, ImportBlocks, ImportScript)
+	}
+
+	provider := Provider()
+	if diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(nil)); diags.HasError() {
+		return fmt.Errorf("failed to configure provider: %v", diags)
+	}
+	c := provider.Meta().(*ArtClient)
+
+	objects, err := listGeneratedResources(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]*hclwrite.File{}
+	variables := hclwrite.NewEmptyFile()
+	var imports []generatedImport
+	names := map[string]bool{}
+
+	for _, o := range objects {
+		r := provider.ResourcesMap[o.resourceType]
+		d := r.Data(nil)
+		d.SetId(o.id)
+
+		if diags := r.ReadContext(ctx, d, c); diags.HasError() {
+			return fmt.Errorf("failed to read %s %s: %v", o.resourceType, o.id, diags)
+		}
+		if d.Id() == "" {
+			log.Printf("[WARN] %s %s disappeared while generating", o.resourceType, o.id)
+			continue
+		}
+
+		name := uniqueResourceName(names, o.resourceType, o.id)
+		file, ok := files[o.resourceType]
+		if !ok {
+			file = hclwrite.NewEmptyFile()
+			files[o.resourceType] = file
+		}
+
+		vars := generateResource(file.Body(), o.resourceType, name, r, d)
+		for _, v := range vars {
+			block := variables.Body().AppendNewBlock("variable", []string{v.name}).Body()
+			block.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+			block.SetAttributeValue("description", cty.StringVal(v.description))
+			variables.Body().AppendNewline()
+		}
+
+		imports = append(imports, generatedImport{o, name})
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for resourceType, file := range files {
+		if err := writeGeneratedFile(dir, resourceType+".tf", file.Bytes()); err != nil {
+			return err
+		}
+	}
+	if len(variables.Body().Blocks()) > 0 {
+		if err := writeGeneratedFile(dir, "variables.tf", variables.Bytes()); err != nil {
+			return err
+		}
+	}
+	return writeImports(dir, importStyle, imports)
+}
+
+// generatedImport is a generated resource along with the name it got in the configuration
+type generatedImport struct {
+	generatedResource
+	name string
+}
+
+// writeImports writes imports either as import blocks to imports.tf or as terraform import commands to import.sh.
+// Only one of them is written, terraform would fail importing a resource the other one already imported
+func writeImports(dir, importStyle string, imports []generatedImport) error {
+	if importStyle == ImportScript {
+		script := []string{"#!/bin/sh", "set -e", ""}
+		for _, i := range imports {
+			script = append(script, fmt.Sprintf("terraform import %s.%s '%s'", i.resourceType, i.name, i.id))
+		}
+		return ioutil.WriteFile(filepath.Join(dir, "import.sh"), []byte(strings.Join(script, "\n")+"\n"), 0755)
+	}
+
+	file := hclwrite.NewEmptyFile()
+	for _, i := range imports {
+		block := file.Body().AppendNewBlock("import", nil).Body()
+		block.SetAttributeTraversal("to", hcl.Traversal{hcl.TraverseRoot{Name: i.resourceType}, hcl.TraverseAttr{Name: i.name}})
+		block.SetAttributeValue("id", cty.StringVal(i.id))
+		file.Body().AppendNewline()
+	}
+	return writeGeneratedFile(dir, "imports.tf", file.Bytes())
+}
+
+func writeGeneratedFile(dir, name string, content []byte) error {
+	return ioutil.WriteFile(filepath.Join(dir, name), hclwrite.Format(content), 0644)
+}
+
+// listGeneratedResources enumerates the objects on the server, repositories first as everything else refers to them.
+// artifactory_group_members and artifactory_permission_target_grant aren't generated, groups and permission targets
+// already hold what they would manage
+func listGeneratedResources(ctx context.Context, c *ArtClient) ([]generatedResource, error) {
+	var objects []generatedResource
+
+	repos, _, err := c.V1.Repositories.ListRepositories(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %s", err)
+	}
+	for _, repo := range *repos {
+		rclass := strings.ToLower(*repo.Type)
+		switch rclass {
+		case "local", "remote", "virtual", "federated":
+			objects = append(objects, generatedResource{fmt.Sprintf("artifactory_%s_repository", rclass), *repo.Key})
+		default:
+			log.Printf("[WARN] skipping %s repository %s, it has no resource", rclass, *repo.Key)
+			continue
+		}
+
+		if rclass == "local" || rclass == "remote" {
+			replication, _, err := c.V1.Artifacts.GetRepositoryReplicationConfig(ctx, *repo.Key)
+			if err == nil && replication.Replications != nil && len(*replication.Replications) > 0 {
+				objects = append(objects, generatedResource{"artifactory_replication_config", *repo.Key})
+			}
+		}
+	}
+
+	groups, _, err := c.V1.Security.ListGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %s", err)
+	}
+	for _, group := range *groups {
+		if !builtinGroups[*group.Name] {
+			objects = append(objects, generatedResource{"artifactory_group", *group.Name})
+		}
+	}
+
+	users, _, err := c.V1.Security.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %s", err)
+	}
+	for _, user := range *users {
+		if !builtinUsers[*user.Name] {
+			objects = append(objects, generatedResource{"artifactory_user", *user.Name})
+		}
+	}
+
+	targets, _, err := c.V1.Security.ListPermissionTargets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permission targets: %s", err)
+	}
+	for _, target := range targets {
+		objects = append(objects, generatedResource{"artifactory_permission_target", *target.Name})
+	}
+
+	certs, _, err := c.V1.Security.GetCertificates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %s", err)
+	}
+	for _, cert := range *certs {
+		objects = append(objects, generatedResource{"artifactory_certificate", *cert.CertificateAlias})
+	}
+
+	config, err := getSystemConfiguration(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ldap settings: %s", err)
+	}
+	for _, setting := range config.Security.LdapSettings {
+		objects = append(objects, generatedResource{"artifactory_ldap_setting", setting.Key})
+	}
+	for _, setting := range config.Security.LdapGroupSettings {
+		objects = append(objects, generatedResource{"artifactory_ldap_group_setting", setting.Name})
+	}
+
+	// a disabled policy is what destroying the resource leaves behind, there is nothing to manage
+	if policy, err := getPasswordExpirationPolicy(ctx, c); err != nil {
+		log.Printf("[WARN] skipping the password expiration policy, it can't be read: %s", err)
+	} else if policy.Enabled {
+		objects = append(objects, generatedResource{"artifactory_password_expiration_policy", passwordExpirationPolicyId})
+	}
+
+	return objects, nil
+}
+
+// builtinUsers and builtinGroups come with every artifactory instance, they aren't generated
+var (
+	builtinUsers  = map[string]bool{"admin": true, "anonymous": true, "_internal": true}
+	builtinGroups = map[string]bool{"readers": true}
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// uniqueResourceName turns id into a valid resource name that isn't used for resourceType yet
+func uniqueResourceName(names map[string]bool, resourceType, id string) string {
+	name := invalidNameChars.ReplaceAllString(id, "_")
+	if name == "" || !(name[0] == '_' || name[0] >= 'a' && name[0] <= 'z' || name[0] >= 'A' && name[0] <= 'Z') {
+		name = "_" + name
+	}
+
+	unique := name
+	for i := 2; names[resourceType+"."+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	names[resourceType+"."+unique] = true
+	return unique
+}
+
+// generateResource appends the resource block of d to body. Only attributes that can be configured and differ from
+// their defaults are written. Secrets can't be read back, they become variables which are returned
+func generateResource(body *hclwrite.Body, resourceType, name string, r *schema.Resource, d *schema.ResourceData) []secretVariable {
+	block := body.AppendNewBlock("resource", []string{resourceType, name}).Body()
+	body.AppendNewline()
+
+	attributes := map[string]*schema.Schema{}
+	values := map[string]interface{}{}
+	for k, attr := range r.Schema {
+		if !generatedSkips[resourceType+"."+k] {
+			attributes[k] = attr
+			values[k] = d.Get(k)
+		}
+	}
+	return generateAttributes(block, resourceType+"."+name, name, attributes, values)
+}
+
+// generatedSkips are attributes left out of generated resources. Group members are generated on the users only,
+// so membership isn't managed in two places
+var generatedSkips = map[string]bool{"artifactory_group.user_names": true}
+
+// generatedSecretOwners are the attributes optional secrets are used with, a secret is only asked for when its owner
+// is set
+var generatedSecretOwners = map[string]string{"password": "username", "manager_password": "manager_dn"}
+
+func generateAttributes(body *hclwrite.Body, address, prefix string, s map[string]*schema.Schema, values map[string]interface{}) []secretVariable {
+	var vars []secretVariable
+
+	for _, k := range generatedAttributeOrder(s) {
+		attr := s[k]
+		if attr.Deprecated != "" || (attr.Computed && !attr.Optional && !attr.Required) {
+			continue
+		}
+
+		if attr.Sensitive {
+			// only ask for secrets that are needed, e.g. a password is only used together with a username
+			if owner, _ := values[generatedSecretOwners[k]].(string); attr.Required || owner != "" {
+				v := secretVariable{name: prefix + "_" + k, description: fmt.Sprintf("%s of %s", k, address)}
+				body.SetAttributeTraversal(k, hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: v.name}})
+				vars = append(vars, v)
+			}
+			continue
+		}
+
+		value := values[k]
+		if isDefaultValue(attr, value) {
+			continue
+		}
+
+		if elem, ok := attr.Elem.(*schema.Resource); ok {
+			for i, item := range generatedList(value) {
+				block := body.AppendNewBlock(k, nil).Body()
+				vars = append(vars, generateAttributes(block, fmt.Sprintf("%s.%s[%d]", address, k, i),
+					fmt.Sprintf("%s_%s_%d", prefix, k, i), elem.Schema, item.(map[string]interface{}))...)
+			}
+			continue
+		}
+
+		body.SetAttributeValue(k, generatedValue(value))
+	}
+	return vars
+}
+
+// generatedAttributeOrder lists required attributes first, then the others, both sorted by name
+func generatedAttributeOrder(s map[string]*schema.Schema) []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if s[keys[i]].Required != s[keys[j]].Required {
+			return s[keys[i]].Required
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func isDefaultValue(attr *schema.Schema, value interface{}) bool {
+	if attr.Required {
+		return false
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return true
+	case *schema.Set:
+		return v.Len() == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	if attr.Default != nil {
+		return value == attr.Default
+	}
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	}
+	return false
+}
+
+func generatedList(value interface{}) []interface{} {
+	if set, ok := value.(*schema.Set); ok {
+		return set.List()
+	}
+	list, _ := value.([]interface{})
+	return list
+}
+
+func generatedValue(value interface{}) cty.Value {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case *schema.Set, []interface{}:
+		var items []cty.Value
+		for _, item := range generatedList(v) {
+			items = append(items, generatedValue(item))
+		}
+		if len(items) == 0 {
+			return cty.EmptyTupleVal
+		}
+		return cty.TupleVal(items)
+	case map[string]interface{}:
+		attrs := map[string]cty.Value{}
+		for k, item := range v {
+			attrs[k] = generatedValue(item)
+		}
+		return cty.ObjectVal(attrs)
+	}
+	return cty.NullVal(cty.DynamicPseudoType)
+}
@@ -0,0 +1,109 @@
+package artifactory
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateResource(t *testing.T) {
+	r := resourceArtifactoryReplicationConfig()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"repo_key": "libs-release",
+		"cron_exp": "0 0 * * * ?",
+		"replications": []interface{}{
+			map[string]interface{}{
+				"url":      "https://example.com/artifactory/libs-release",
+				"username": "replicator",
+				"enabled":  true,
+			},
+			map[string]interface{}{
+				"url": "https://example.org/artifactory/libs-release",
+			},
+		},
+	})
+
+	file := hclwrite.NewEmptyFile()
+	vars := generateResource(file.Body(), "artifactory_replication_config", "libs-release", r, d)
+
+	assert.Equal(t, []secretVariable{{
+		name:        "libs-release_replications_0_password",
+		description: "password of artifactory_replication_config.libs-release.replications[0]",
+	}}, vars)
+	assert.Equal(t, `resource "artifactory_replication_config" "libs-release" {
+  cron_exp = "0 0 * * * ?"
+  repo_key = "libs-release"
+  replications {
+    url      = "https://example.com/artifactory/libs-release"
+    enabled  = true
+    password = var.libs-release_replications_0_password
+    username = "replicator"
+  }
+  replications {
+    url = "https://example.org/artifactory/libs-release"
+  }
+}
+
+`, string(hclwrite.Format(file.Bytes())))
+}
+
+func TestGenerateResource_ldapSetting(t *testing.T) {
+	r := resourceArtifactoryLdapSetting()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"key":        "ldap1",
+		"url":        "ldap://ldap.example.com",
+		"manager_dn": "cn=manager",
+	})
+
+	file := hclwrite.NewEmptyFile()
+	vars := generateResource(file.Body(), "artifactory_ldap_setting", "ldap1", r, d)
+
+	// the manager password is only needed with a manager dn
+	assert.Equal(t, []secretVariable{{
+		name:        "ldap1_manager_password",
+		description: "manager_password of artifactory_ldap_setting.ldap1",
+	}}, vars)
+}
+
+func TestUniqueResourceName(t *testing.T) {
+	names := map[string]bool{}
+
+	assert.Equal(t, "libs-release", uniqueResourceName(names, "artifactory_local_repository", "libs-release"))
+	assert.Equal(t, "libs-release", uniqueResourceName(names, "artifactory_replication_config", "libs-release"))
+	assert.Equal(t, "libs-release_2", uniqueResourceName(names, "artifactory_local_repository", "libs-release"))
+	assert.Equal(t, "jane_example_com", uniqueResourceName(names, "artifactory_user", "jane@example.com"))
+	assert.Equal(t, "_1password", uniqueResourceName(names, "artifactory_user", "1password"))
+}
+
+func TestWriteImports(t *testing.T) {
+	imports := []generatedImport{{generatedResource{"artifactory_user", "jane@example.com"}, "jane_example_com"}}
+
+	for style, want := range map[string]string{
+		ImportBlocks: "import {\n  to = artifactory_user.jane_example_com\n  id = \"jane@example.com\"\n}\n\n",
+		ImportScript: "#!/bin/sh\nset -e\n\nterraform import artifactory_user.jane_example_com 'jane@example.com'\n",
+	} {
+		dir, err := ioutil.TempDir("", "generate")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		assert.NoError(t, writeImports(dir, style, imports))
+		files, err := ioutil.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, files, 1, style)
+
+		content, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(content), style)
+	}
+}
+
+func TestGenerate_importStyle(t *testing.T) {
+	err := Generate(context.Background(), "", "both")
+	assert.EqualError(t, err, `unknown import style "both", use blocks or script`)
+}
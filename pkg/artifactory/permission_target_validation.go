@@ -0,0 +1,162 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// specialRepositories are the keywords artifactory accepts in place of repository keys
+var specialRepositories = []string{"ANY", "ANY LOCAL", "ANY REMOTE", "ANY DISTRIBUTION"}
+
+// buildInfoRepository is the only repository a build section can refer to
+const buildInfoRepository = "artifactory-build-info"
+
+var permissionTargetSections = []string{"repo", "build", "release_bundle"}
+
+// looksLikeSpecialRepository catches misspelled keywords. Repository keys can't contain spaces, so anything
+// starting with "any " is meant as one
+func looksLikeSpecialRepository(key string) bool {
+	upper := strings.ToUpper(key)
+	return upper == "ANY" || strings.HasPrefix(upper, "ANY ")
+}
+
+// checkSectionRepositories checks the repositories of a section without asking the server
+func checkSectionRepositories(section string, repositories []string) []string {
+	var errs []string
+	for _, key := range repositories {
+		switch {
+		case section == "build":
+			if key != buildInfoRepository {
+				errs = append(errs, fmt.Sprintf("build repositories can only be %s, got %s", buildInfoRepository, key))
+			}
+		case looksLikeSpecialRepository(key) && !containsString(specialRepositories, key):
+			errs = append(errs, fmt.Sprintf("%s repositories: unknown keyword %q, expected one of %s", section, key, strings.Join(specialRepositories, ", ")))
+		}
+	}
+	return errs
+}
+
+// permissionTargetReferences collects what the sections of a planned permission target refer to
+type permissionTargetReferences struct {
+	repositories map[string][]string
+	users        map[string][]string
+	groups       map[string][]string
+}
+
+func principalNames(actions map[string]interface{}, key string) []string {
+	var names []string
+	if set, ok := actions[key].(*schema.Set); ok {
+		for _, v := range set.List() {
+			names = append(names, v.(map[string]interface{})["name"].(string))
+		}
+	}
+	return names
+}
+
+func newPermissionTargetReferences(d *schema.ResourceDiff) (*permissionTargetReferences, bool) {
+	refs := &permissionTargetReferences{
+		repositories: map[string][]string{},
+		users:        map[string][]string{},
+		groups:       map[string][]string{},
+	}
+
+	for _, section := range permissionTargetSections {
+		if !d.NewValueKnown(section+".0.repositories") || !d.NewValueKnown(section+".0.actions") {
+			return nil, false
+		}
+
+		v := d.Get(section).([]interface{})
+		if len(v) == 0 || v[0] == nil {
+			continue
+		}
+		data := v[0].(map[string]interface{})
+
+		refs.repositories[section] = castToStringArr(data["repositories"].(*schema.Set).List())
+		if actions, ok := data["actions"].([]interface{}); ok && len(actions) > 0 && actions[0] != nil {
+			refs.users[section] = principalNames(actions[0].(map[string]interface{}), "users")
+			refs.groups[section] = principalNames(actions[0].(map[string]interface{}), "groups")
+		}
+	}
+	return refs, true
+}
+
+// checkServer reports users, groups and repositories that don't exist on the server. Special repositories and the
+// build info repository are not listed by the repositories api
+func (refs *permissionTargetReferences) checkServer(ctx context.Context, c *ArtClient) ([]string, error) {
+	repos, err := listRepositories(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	userList, _, err := c.V1.Security.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %s", err)
+	}
+	groupList, _, err := c.V1.Security.ListGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %s", err)
+	}
+
+	users := map[string]bool{}
+	for _, u := range *userList {
+		users[*u.Name] = true
+	}
+	groups := map[string]bool{}
+	for _, g := range *groupList {
+		groups[*g.Name] = true
+	}
+
+	var errs []string
+	for _, section := range permissionTargetSections {
+		for _, key := range refs.repositories[section] {
+			if _, ok := repos[key]; !ok && section != "build" && !looksLikeSpecialRepository(key) {
+				errs = append(errs, fmt.Sprintf("%s repositories: repository %s does not exist", section, key))
+			}
+		}
+		for _, name := range refs.users[section] {
+			if !users[name] {
+				errs = append(errs, fmt.Sprintf("%s actions: user %s does not exist", section, name))
+			}
+		}
+		for _, name := range refs.groups[section] {
+			if !groups[name] {
+				errs = append(errs, fmt.Sprintf("%s actions: group %s does not exist", section, name))
+			}
+		}
+	}
+	return errs, nil
+}
+
+// permissionTargetCustomizeDiff rejects unknown repository keywords and build sections that refer to anything but the
+// build info repository. With validate_principals the users, groups and repositories must also exist on the server
+// at plan time, so they can't be created in the same apply. It is skipped while sections depend on other resources
+func permissionTargetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("repo") && !d.HasChange("build") && !d.HasChange("release_bundle") && !d.HasChange("validate_principals") {
+		return nil
+	}
+
+	refs, known := newPermissionTargetReferences(d)
+	if !known {
+		return nil
+	}
+
+	var errs []string
+	for _, section := range permissionTargetSections {
+		errs = append(errs, checkSectionRepositories(section, refs.repositories[section])...)
+	}
+
+	if d.Get("validate_principals").(bool) {
+		serverErrs, err := refs.checkServer(ctx, m.(*ArtClient))
+		if err != nil {
+			return err
+		}
+		errs = append(errs, serverErrs...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid permission target %s:\n%s", d.Get("name"), strings.Join(errs, "\n"))
+	}
+	return nil
+}
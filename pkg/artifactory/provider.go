@@ -2,18 +2,30 @@ package artifactory
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/rickardl/go-artifactory/v2/artifactory/client"
 	"github.com/rickardl/go-artifactory/v2/artifactory/transport"
 )
 
+// ArtClient is the provider meta. It embeds the go-artifactory client and keeps the raw api client around
+// for the endpoints go-artifactory does not wrap yet
+type ArtClient struct {
+	*artifactory.Artifactory
+
+	Raw *client.Client
+
+	// memberships are the group membership claims of the resources planned so far. Terraform configures a new
+	// provider for every run, so they don't outlive a plan
+	memberships *membershipClaims
+}
+
 // Artifactory Provider that supports configuration via username+password or a token
 // Supported resources are repos, users, groups, replications, and permissions
-func Provider() terraform.ResourceProvider {
+func Provider() *schema.Provider {
 
 	// The actual provider
 	provider := &schema.Provider{Schema: map[string]*schema.Schema{
@@ -60,25 +72,53 @@ func Provider() terraform.ResourceProvider {
 	},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"artifactory_local_repository":          resourceArtifactoryLocalRepository(),
-			"artifactory_remote_repository":         resourceArtifactoryRemoteRepository(),
-			"artifactory_virtual_repository":        resourceArtifactoryVirtualRepository(),
-			"artifactory_group":                     resourceArtifactoryGroup(),
-			"artifactory_user":                      resourceArtifactoryUser(),
-			"artifactory_permission_target":         resourceArtifactoryPermissionTarget(),
-			"artifactory_replication_config":        resourceArtifactoryReplicationConfig(),
-			"artifactory_single_replication_config": resourceArtifactorySingleReplicationConfig(),
-			"artifactory_certificate":               resourceArtifactoryCertificate(),
+			"artifactory_local_repository":           resourceArtifactoryLocalRepository(),
+			"artifactory_remote_repository":          resourceArtifactoryRemoteRepository(),
+			"artifactory_virtual_repository":         resourceArtifactoryVirtualRepository(),
+			"artifactory_federated_repository":       resourceArtifactoryFederatedRepository(),
+			"artifactory_repository_json":            resourceArtifactoryRepositoryJson(),
+			"artifactory_local_docker_repository":    resourceArtifactoryLocalDockerRepository(),
+			"artifactory_local_maven_repository":     resourceArtifactoryLocalMavenRepository(),
+			"artifactory_local_npm_repository":       resourceArtifactoryLocalNpmRepository(),
+			"artifactory_local_helm_repository":      resourceArtifactoryLocalHelmRepository(),
+			"artifactory_local_rpm_repository":       resourceArtifactoryLocalRpmRepository(),
+			"artifactory_local_debian_repository":    resourceArtifactoryLocalDebianRepository(),
+			"artifactory_remote_docker_repository":   resourceArtifactoryRemoteDockerRepository(),
+			"artifactory_remote_maven_repository":    resourceArtifactoryRemoteMavenRepository(),
+			"artifactory_remote_npm_repository":      resourceArtifactoryRemoteNpmRepository(),
+			"artifactory_remote_helm_repository":     resourceArtifactoryRemoteHelmRepository(),
+			"artifactory_remote_pypi_repository":     resourceArtifactoryRemotePypiRepository(),
+			"artifactory_virtual_docker_repository":  resourceArtifactoryVirtualDockerRepository(),
+			"artifactory_virtual_maven_repository":   resourceArtifactoryVirtualMavenRepository(),
+			"artifactory_virtual_npm_repository":     resourceArtifactoryVirtualNpmRepository(),
+			"artifactory_virtual_helm_repository":    resourceArtifactoryVirtualHelmRepository(),
+			"artifactory_group":                      resourceArtifactoryGroup(),
+			"artifactory_group_members":              resourceArtifactoryGroupMembers(),
+			"artifactory_user":                       resourceArtifactoryUser(),
+			"artifactory_ldap_setting":               resourceArtifactoryLdapSetting(),
+			"artifactory_ldap_group_setting":         resourceArtifactoryLdapGroupSetting(),
+			"artifactory_password_expiration_policy": resourceArtifactoryPasswordExpirationPolicy(),
+			"artifactory_permission_target":          resourceArtifactoryPermissionTarget(),
+			"artifactory_permission_target_grant":    resourceArtifactoryPermissionTargetGrant(),
+			"artifactory_permission_target_v1":       resourceArtifactoryPermissionTargetV1(),
+			"artifactory_replication_config":         resourceArtifactoryReplicationConfig(),
+			"artifactory_single_replication_config":  resourceArtifactorySingleReplicationConfig(),
+			"artifactory_certificate":                resourceArtifactoryCertificate(),
+			"artifactory_api_key":                    resourceArtifactoryApiKey(),
+			"artifactory_access_token":               resourceArtifactoryAccessToken(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"artifactory_file":             dataSourceArtifactoryFile(),
-			"artifactory_fileinfo":         dataSourceArtifactoryFileInfo(),
-			"artifactory_group":            dataSourceArtifactoryGroup(),
-			"artifactory_local_repository": dataSourceArtifactoryLocalRepository(),
+			"artifactory_effective_permissions": dataSourceArtifactoryEffectivePermissions(),
+			"artifactory_file":                  dataSourceArtifactoryFile(),
+			"artifactory_fileinfo":              dataSourceArtifactoryFileInfo(),
+			"artifactory_group":                 dataSourceArtifactoryGroup(),
+			"artifactory_local_repository":      dataSourceArtifactoryLocalRepository(),
+			"artifactory_permission_targets":    dataSourceArtifactoryPermissionTargets(),
+			"artifactory_user":                  dataSourceArtifactoryUser(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 
 	return provider
@@ -86,9 +126,9 @@ func Provider() terraform.ResourceProvider {
 }
 
 // Creates the client for artifactory, will prefer token auth over basic auth if both set
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	if d.Get("url") == nil {
-		return nil, fmt.Errorf("url cannot be nil")
+		return nil, diag.Errorf("url cannot be nil")
 	}
 
 	username := d.Get("username").(string)
@@ -99,41 +139,55 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	// Deprecated
 	token := d.Get("token").(string)
 
-	var client *http.Client
+	var diags diag.Diagnostics
+	var httpClient *http.Client
 	if username != "" && password != "" {
 		tp := transport.BasicAuth{
 			Username: username,
 			Password: password,
 		}
-		client = tp.Client()
+		httpClient = tp.Client()
 	} else if apiKey != "" {
 		tp := &transport.ApiKeyAuth{
 			ApiKey: apiKey,
 		}
-		client = tp.Client()
+		httpClient = tp.Client()
 	} else if accessToken != "" {
 		tp := &transport.AccessTokenAuth{
 			AccessToken: accessToken,
 		}
-		client = tp.Client()
+		httpClient = tp.Client()
 	} else if token != "" {
 		tp := &transport.ApiKeyAuth{
 			ApiKey: token,
 		}
-		client = tp.Client()
+		httpClient = tp.Client()
+		// the schema only warns about token in the config, ARTIFACTORY_TOKEN goes unnoticed otherwise
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "token is deprecated",
+			Detail:        "Since v1.5 token and ARTIFACTORY_TOKEN are renamed to api_key and ARTIFACTORY_API_KEY",
+			AttributePath: attributePath("token"),
+		})
 	} else {
-		return nil, fmt.Errorf("either [username, password] or [api_key] or [access_token] must be set to use provider")
+		return nil, diag.Errorf("either [username, password] or [api_key] or [access_token] must be set to use provider")
 	}
 
-	rt, err := artifactory.NewClient(d.Get("url").(string), client)
+	rt, err := artifactory.NewClient(d.Get("url").(string), httpClient)
+	if err != nil {
+		return nil, append(diags, attributeDiag("url", err)...)
+	}
 
+	raw, err := client.NewClient(d.Get("url").(string), httpClient)
 	if err != nil {
-		return nil, err
-	} else if _, resp, err := rt.V1.System.Ping(context.Background()); err != nil {
-		return nil, err
+		return nil, append(diags, attributeDiag("url", err)...)
+	}
+
+	if _, resp, err := rt.V1.System.Ping(ctx); err != nil {
+		return nil, append(diags, diag.FromErr(err)...)
 	} else if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to ping server. Got %d", resp.StatusCode)
+		return nil, append(diags, diag.Errorf("failed to ping server. Got %d", resp.StatusCode)...)
 	}
 
-	return rt, nil
+	return &ArtClient{Artifactory: rt, Raw: raw, memberships: newMembershipClaims()}, diags
 }
@@ -0,0 +1,900 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rickardl/go-artifactory/v2/artifactory"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+// mergeSchema combines several schema maps into one. Later maps win on duplicate keys
+func mergeSchema(schemata ...map[string]*schema.Schema) map[string]*schema.Schema {
+	result := make(map[string]*schema.Schema)
+	for _, s := range schemata {
+		for k, v := range s {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// baseLocalRepoSchema holds the attributes every local repository has, regardless of package type
+func baseLocalRepoSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"key": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"notes": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"includes_pattern": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"excludes_pattern": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"repo_layout_ref": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"blacked_out": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"property_sets": {
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Set:      schema.HashString,
+			Optional: true,
+		},
+		"archive_browsing_enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"xray_index": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"force_destroy": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+}
+
+func unpackBaseLocalRepo(d *ResourceData, repo *v1.LocalRepository) {
+	repo.Key = d.getStringRef("key", false)
+	repo.RClass = artifactory.String("local")
+
+	repo.Description = d.getStringRef("description", true)
+	repo.Notes = d.getStringRef("notes", true)
+	repo.IncludesPattern = d.getStringRef("includes_pattern", true)
+	repo.ExcludesPattern = d.getStringRef("excludes_pattern", true)
+	repo.RepoLayoutRef = d.getStringRef("repo_layout_ref", true)
+	repo.BlackedOut = d.getBoolRef("blacked_out", true)
+	repo.PropertySets = d.getSetRef("property_sets")
+	repo.ArchiveBrowsingEnabled = d.getBoolRef("archive_browsing_enabled", true)
+	repo.XrayIndex = d.getBoolRef("xray_index", true)
+}
+
+func packBaseLocalRepo(repo *v1.LocalRepository, d *schema.ResourceData, p *packer) {
+	p.set("key", repo.Key)
+	p.set("description", repo.Description)
+	p.set("notes", repo.Notes)
+	p.set("includes_pattern", repo.IncludesPattern)
+	p.set("excludes_pattern", repo.ExcludesPattern)
+	p.set("repo_layout_ref", repo.RepoLayoutRef)
+	p.set("blacked_out", repo.BlackedOut)
+	p.set("archive_browsing_enabled", repo.ArchiveBrowsingEnabled)
+	p.set("xray_index", repo.XrayIndex)
+
+	if repo.PropertySets != nil {
+		p.set("property_sets", schema.NewSet(schema.HashString, castToInterfaceArr(*repo.PropertySets)))
+	}
+}
+
+// baseRemoteRepoSchema holds the attributes every remote repository has, regardless of package type
+func baseRemoteRepoSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"key": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+			DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
+				return old == fmt.Sprintf("%s (local file cache)", new)
+			},
+		},
+		"notes": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"includes_pattern": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"excludes_pattern": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"repo_layout_ref": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"url": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"username": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"password":         secretSchema(),
+		"password_version": secretVersionSchema(),
+		"proxy": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"remote_repo_checksum_policy_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ValidateFunc: validation.StringInSlice([]string{
+				"generate-if-absent",
+				"fail",
+				"ignore-and-generate",
+				"pass-thru",
+			}, false),
+		},
+		"hard_fail": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"offline": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"blacked_out": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"store_artifacts_locally": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"socket_timeout_millis": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+		"local_address": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"retrieval_cache_period_seconds": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+		"missed_cache_period_seconds": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+		"unused_artifacts_cleanup_period_hours": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+		"share_configuration": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"synchronize_properties": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"block_mismatching_mime_types": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"property_sets": {
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Set:      schema.HashString,
+			Optional: true,
+		},
+		"allow_any_host_auth": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"enable_cookie_management": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"client_tls_certificate": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"bypass_head_requests": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"xray_index": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"content_synchronisation": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"enabled": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
+					"statistics_enabled": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
+					"properties_enabled": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
+					"source_origin_absence_detection": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
+				},
+			},
+		},
+		"force_destroy": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+}
+
+func unpackBaseRemoteRepo(d *ResourceData, repo *v1.RemoteRepository) {
+	repo.Key = d.getStringRef("key", false)
+	repo.RClass = artifactory.String("remote")
+
+	repo.Description = d.getStringRef("description", true)
+	repo.Notes = d.getStringRef("notes", true)
+	repo.IncludesPattern = d.getStringRef("includes_pattern", true)
+	repo.ExcludesPattern = d.getStringRef("excludes_pattern", true)
+	repo.RepoLayoutRef = d.getStringRef("repo_layout_ref", true)
+	repo.Url = d.getStringRef("url", true)
+	repo.Username = d.getStringRef("username", true)
+	repo.Password = d.getSecretRef("password")
+	repo.Proxy = d.getStringRef("proxy", true)
+	repo.RemoteRepoChecksumPolicyType = d.getStringRef("remote_repo_checksum_policy_type", true)
+	repo.HardFail = d.getBoolRef("hard_fail", true)
+	repo.Offline = d.getBoolRef("offline", true)
+	repo.BlackedOut = d.getBoolRef("blacked_out", true)
+	repo.StoreArtifactsLocally = d.getBoolRef("store_artifacts_locally", true)
+	repo.SocketTimeoutMillis = d.getIntRef("socket_timeout_millis", true)
+	repo.LocalAddress = d.getStringRef("local_address", true)
+	repo.RetrievalCachePeriodSecs = d.getIntRef("retrieval_cache_period_seconds", true)
+	repo.MissedRetrievalCachePeriodSecs = d.getIntRef("missed_cache_period_seconds", true)
+	repo.UnusedArtifactsCleanupPeriodHours = d.getIntRef("unused_artifacts_cleanup_period_hours", true)
+	repo.ShareConfiguration = d.getBoolRef("share_configuration", true)
+	repo.SynchronizeProperties = d.getBoolRef("synchronize_properties", true)
+	repo.BlockMismatchingMimeTypes = d.getBoolRef("block_mismatching_mime_types", true)
+	repo.PropertySets = d.getSetRef("property_sets")
+	repo.AllowAnyHostAuth = d.getBoolRef("allow_any_host_auth", true)
+	repo.EnableCookieManagement = d.getBoolRef("enable_cookie_management", true)
+	repo.ClientTLSCertificate = d.getStringRef("client_tls_certificate", true)
+	repo.BypassHeadRequests = d.getBoolRef("bypass_head_requests", true)
+	repo.XrayIndex = d.getBoolRef("xray_index", true)
+
+	if v, ok := d.GetOk("content_synchronisation"); ok && d.HasChange("content_synchronisation") && v.([]interface{})[0] != nil {
+		sync := v.([]interface{})[0].(map[string]interface{})
+		repo.ContentSynchronisation = &v1.ContentSynchronisation{
+			Enabled:    artifactory.Bool(sync["enabled"].(bool)),
+			Statistics: &v1.Statistics{Enabled: artifactory.Bool(sync["statistics_enabled"].(bool))},
+			Properties: &v1.Properties{Enabled: artifactory.Bool(sync["properties_enabled"].(bool))},
+			Source:     &v1.Source{OriginAbsenceDetection: artifactory.Bool(sync["source_origin_absence_detection"].(bool))},
+		}
+	}
+}
+
+func packBaseRemoteRepo(repo *v1.RemoteRepository, d *schema.ResourceData, p *packer) {
+	p.set("key", repo.Key)
+	p.set("description", repo.Description)
+	p.set("notes", repo.Notes)
+	p.set("includes_pattern", repo.IncludesPattern)
+	p.set("excludes_pattern", repo.ExcludesPattern)
+	p.set("repo_layout_ref", repo.RepoLayoutRef)
+	p.set("url", repo.Url)
+	p.set("username", repo.Username)
+	p.set("proxy", repo.Proxy)
+	p.set("remote_repo_checksum_policy_type", repo.RemoteRepoChecksumPolicyType)
+	p.set("hard_fail", repo.HardFail)
+	p.set("offline", repo.Offline)
+	p.set("blacked_out", repo.BlackedOut)
+	p.set("store_artifacts_locally", repo.StoreArtifactsLocally)
+	p.set("socket_timeout_millis", repo.SocketTimeoutMillis)
+	p.set("local_address", repo.LocalAddress)
+	p.set("retrieval_cache_period_seconds", repo.RetrievalCachePeriodSecs)
+	p.set("missed_cache_period_seconds", repo.MissedRetrievalCachePeriodSecs)
+	p.set("unused_artifacts_cleanup_period_hours", repo.UnusedArtifactsCleanupPeriodHours)
+	p.set("share_configuration", repo.ShareConfiguration)
+	p.set("synchronize_properties", repo.SynchronizeProperties)
+	p.set("block_mismatching_mime_types", repo.BlockMismatchingMimeTypes)
+	p.set("allow_any_host_auth", repo.AllowAnyHostAuth)
+	p.set("enable_cookie_management", repo.EnableCookieManagement)
+	p.set("client_tls_certificate", repo.ClientTLSCertificate)
+	p.set("bypass_head_requests", repo.BypassHeadRequests)
+	p.set("xray_index", repo.XrayIndex)
+
+	if repo.PropertySets != nil {
+		p.set("property_sets", schema.NewSet(schema.HashString, castToInterfaceArr(*repo.PropertySets)))
+	}
+
+	if sync := repo.ContentSynchronisation; sync != nil {
+		packed := map[string]interface{}{
+			"enabled":                         sync.Enabled != nil && *sync.Enabled,
+			"statistics_enabled":              sync.Statistics != nil && sync.Statistics.Enabled != nil && *sync.Statistics.Enabled,
+			"properties_enabled":              sync.Properties != nil && sync.Properties.Enabled != nil && *sync.Properties.Enabled,
+			"source_origin_absence_detection": sync.Source != nil && sync.Source.OriginAbsenceDetection != nil && *sync.Source.OriginAbsenceDetection,
+		}
+		p.set("content_synchronisation", []interface{}{packed})
+	}
+
+	// the password comes back encrypted, the state keeps the digest of the configured one
+	p.set("password", stateSecretDigest(d, "password"))
+}
+
+// baseVirtualRepoSchema holds the attributes every virtual repository has, regardless of package type
+func baseVirtualRepoSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"key": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"repositories": {
+			Type:     schema.TypeList,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Required: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"notes": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"includes_pattern": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "**/*",
+		},
+		"excludes_pattern": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"repo_layout_ref": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"resolved_repositories": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"artifactory_requests_can_retrieve_remote_artifacts": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"default_deployment_repo": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	}
+}
+
+func unpackBaseVirtualRepo(d *ResourceData, repo *v1.VirtualRepository) {
+	repo.Key = d.getStringRef("key", false)
+	repo.RClass = artifactory.String("virtual")
+
+	repo.Repositories = d.getListRef("repositories")
+	repo.Description = d.getStringRef("description", true)
+	repo.Notes = d.getStringRef("notes", true)
+	repo.IncludesPattern = d.getStringRef("includes_pattern", true)
+	repo.ExcludesPattern = d.getStringRef("excludes_pattern", true)
+	repo.RepoLayoutRef = d.getStringRef("repo_layout_ref", true)
+	repo.ArtifactoryRequestsCanRetrieveRemoteArtifacts = d.getBoolRef("artifactory_requests_can_retrieve_remote_artifacts", true)
+	repo.DefaultDeploymentRepo = d.getStringRef("default_deployment_repo", true)
+}
+
+func packBaseVirtualRepo(repo *v1.VirtualRepository, d *schema.ResourceData, p *packer) {
+	p.set("key", repo.Key)
+	p.set("repositories", repo.Repositories)
+	p.set("description", repo.Description)
+	p.set("notes", repo.Notes)
+	p.set("includes_pattern", repo.IncludesPattern)
+	p.set("excludes_pattern", repo.ExcludesPattern)
+	p.set("repo_layout_ref", repo.RepoLayoutRef)
+	p.set("artifactory_requests_can_retrieve_remote_artifacts", repo.ArtifactoryRequestsCanRetrieveRemoteArtifacts)
+	p.set("default_deployment_repo", repo.DefaultDeploymentRepo)
+}
+
+// isArtifactoryUrl guesses whether a remote url points at another Artifactory instance, either self hosted under
+// the usual /artifactory context path or on jfrog.io
+func isArtifactoryUrl(rawUrl string) bool {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(u.Path+"/", "/artifactory/") || strings.HasSuffix(u.Hostname(), ".jfrog.io")
+}
+
+// smartRemoteCustomizeDiff only allows content synchronisation for smart remotes, i.e. remotes of another Artifactory.
+// Other servers don't speak the protocol and Artifactory would silently ignore the settings
+func smartRemoteCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.HasChange("content_synchronisation") && !d.HasChange("url") {
+		return nil
+	}
+	if !d.NewValueKnown("url") {
+		return nil
+	}
+
+	v, ok := d.GetOk("content_synchronisation")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		return nil
+	}
+
+	for _, enabled := range v.([]interface{})[0].(map[string]interface{}) {
+		if enabled.(bool) && !isArtifactoryUrl(d.Get("url").(string)) {
+			return fmt.Errorf("content_synchronisation can only be enabled when url points at another Artifactory instance, got %s", d.Get("url").(string))
+		}
+	}
+	return nil
+}
+
+// packageTypeSchema exposes the fixed package type of the package type specific resources
+func packageTypeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"package_type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+// checkPackageType makes sure a package type specific resource is not pointed at a repository of another type,
+// e.g. when importing
+func checkPackageType(key string, actual *string, expected string) error {
+	if actual != nil && *actual != expected {
+		return fmt.Errorf("repository %s has package type %s, expected %s", key, *actual, expected)
+	}
+	return nil
+}
+
+// localPackageTypes are the package types Artifactory supports for local repositories
+var localPackageTypes = []string{
+	"bower", "chef", "cocoapods", "composer", "conan", "conda", "cran", "debian", "docker", "gems", "generic",
+	"gitlfs", "go", "gradle", "helm", "ivy", "maven", "npm", "nuget", "opkg", "puppet", "pypi", "rpm", "sbt", "vagrant",
+}
+
+// remotePackageTypes adds the types that can only be proxied to the local ones
+var remotePackageTypes = append([]string{"p2", "vcs"}, localPackageTypes...)
+
+// virtualPackageTypes adds p2, which can be aggregated but not hosted, to the local ones
+var virtualPackageTypes = append([]string{"p2"}, localPackageTypes...)
+
+var mavenLikePackageTypes = []string{"maven", "gradle", "ivy", "sbt"}
+
+// localPackageTypeAttributes lists the attributes of the generic local repository that only apply to some package types
+var localPackageTypeAttributes = map[string][]string{
+	"handle_releases":                 mavenLikePackageTypes,
+	"handle_snapshots":                mavenLikePackageTypes,
+	"max_unique_snapshots":            mavenLikePackageTypes,
+	"checksum_policy_type":            mavenLikePackageTypes,
+	"snapshot_version_behavior":       mavenLikePackageTypes,
+	"suppress_pom_consistency_checks": mavenLikePackageTypes,
+	"debian_trivial_layout":           {"debian"},
+	"max_unique_tags":                 {"docker"},
+	"docker_api_version":              {"docker"},
+	"calculate_yum_metadata":          {"rpm"},
+	"yum_root_depth":                  {"rpm"},
+	"enable_file_lists_indexing":      {"rpm"},
+}
+
+// remotePackageTypeAttributes lists the attributes of the generic remote repository that only apply to some package types
+var remotePackageTypeAttributes = map[string][]string{
+	"handle_releases":                 mavenLikePackageTypes,
+	"handle_snapshots":                mavenLikePackageTypes,
+	"max_unique_snapshots":            mavenLikePackageTypes,
+	"suppress_pom_consistency_checks": mavenLikePackageTypes,
+	"fetch_jars_eagerly":              mavenLikePackageTypes,
+	"fetch_sources_eagerly":           mavenLikePackageTypes,
+	"pypi_registry_url":               {"pypi"},
+	"bower_registry_url":              {"bower"},
+	"enable_token_authentication":     {"docker"},
+	"vcs_type":                        {"vcs", "bower", "cocoapods", "composer", "go"},
+	"vcs_git_provider":                {"vcs", "bower", "cocoapods", "composer", "go"},
+	"vcs_git_download_url":            {"vcs", "bower", "cocoapods", "composer", "go"},
+	"feed_context_path":               {"nuget"},
+	"download_context_path":           {"nuget"},
+	"v3_feed_url":                     {"nuget"},
+	"nuget":                           {"nuget"},
+}
+
+// virtualPackageTypeAttributes lists the attributes of the generic virtual repository that only apply to some package types
+var virtualPackageTypeAttributes = map[string][]string{
+	"debian_trivial_layout":                    {"debian"},
+	"pom_repository_references_cleanup_policy": mavenLikePackageTypes,
+}
+
+// packageTypeCustomizeDiff rejects unsupported package types and attributes that do not apply to the chosen one at plan
+// time, instead of having the server ignore them or fail on apply. Only attributes that change are checked, so values
+// the server reports back for other package types don't trip it
+func packageTypeCustomizeDiff(packageTypes []string, attributes map[string][]string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if !d.NewValueKnown("package_type") {
+			return nil
+		}
+		packageType := d.Get("package_type").(string)
+		if packageType == "" {
+			return nil
+		}
+		if !containsString(packageTypes, packageType) {
+			return fmt.Errorf("package_type %q is not supported, expected one of %s", packageType, strings.Join(packageTypes, ", "))
+		}
+
+		var errs []string
+		for attr, types := range attributes {
+			if _, ok := d.GetOk(attr); ok && d.HasChange(attr) && !containsString(types, packageType) {
+				errs = append(errs, fmt.Sprintf("%s only applies to package types %s", attr, strings.Join(types, ", ")))
+			}
+		}
+		if len(errs) > 0 {
+			sort.Strings(errs)
+			return fmt.Errorf("attributes not supported for package_type %q:\n%s", packageType, strings.Join(errs, "\n"))
+		}
+		return nil
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// localRepositoryResource builds a package type specific local repository resource on top of the base local schema.
+// unpack and pack only need to handle the attributes in extra and may be nil if there are none
+func localRepositoryResource(packageType string, extra map[string]*schema.Schema,
+	unpack func(*ResourceData, *v1.LocalRepository), pack func(*v1.LocalRepository, *schema.ResourceData, *packer)) *schema.Resource {
+
+	unpackRepo := func(s *schema.ResourceData) *v1.LocalRepository {
+		d := &ResourceData{s}
+		repo := new(v1.LocalRepository)
+
+		unpackBaseLocalRepo(d, repo)
+		repo.PackageType = artifactory.String(packageType)
+		if unpack != nil {
+			unpack(d, repo)
+		}
+
+		return repo
+	}
+
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		c := m.(*ArtClient)
+
+		repo, resp, err := c.V1.Repositories.GetLocal(ctx, d.Id())
+		if repositoryNotFound(resp) {
+			d.SetId("")
+			return nil
+		} else if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := checkPackageType(d.Id(), repo.PackageType, packageType); err != nil {
+			return diag.FromErr(err)
+		}
+
+		p := newPacker("artifactory_local_"+packageType+"_repository", d)
+
+		packBaseLocalRepo(repo, d, p)
+		p.set("package_type", repo.PackageType)
+		if pack != nil {
+			pack(repo, d, p)
+		}
+
+		return diag.FromErr(p.err())
+	}
+
+	resourceSchema := mergeSchema(baseLocalRepoSchema(), packageTypeSchema(), extra)
+
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			repo := unpackRepo(d)
+			if _, err := c.V1.Repositories.CreateLocal(ctx, repo); err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(*repo.Key)
+			return read(ctx, d, m)
+		},
+		ReadContext: read,
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			repo := unpackRepo(d)
+			if _, err := c.V1.Repositories.UpdateLocal(ctx, d.Id(), repo); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return read(ctx, d, m)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			if diags := checkRepositoryDestroy(ctx, c, d, d.Id()); diags.HasError() {
+				return diags
+			}
+
+			resp, err := c.V1.Repositories.DeleteLocal(ctx, d.Id())
+			if repositoryNotFound(resp) {
+				return nil
+			}
+			return diag.FromErr(err)
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+// remoteRepositoryResource builds a package type specific remote repository resource on top of the base remote schema.
+// unpack and pack only need to handle the attributes in extra and may be nil if there are none
+func remoteRepositoryResource(packageType string, extra map[string]*schema.Schema,
+	unpack func(*ResourceData, *v1.RemoteRepository), pack func(*v1.RemoteRepository, *schema.ResourceData, *packer)) *schema.Resource {
+
+	unpackRepo := func(s *schema.ResourceData) *v1.RemoteRepository {
+		d := &ResourceData{s}
+		repo := new(v1.RemoteRepository)
+
+		unpackBaseRemoteRepo(d, repo)
+		repo.PackageType = artifactory.String(packageType)
+		if unpack != nil {
+			unpack(d, repo)
+		}
+
+		return repo
+	}
+
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		c := m.(*ArtClient)
+
+		repo, resp, err := c.V1.Repositories.GetRemote(ctx, d.Id())
+		if repositoryNotFound(resp) {
+			d.SetId("")
+			return nil
+		} else if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := checkPackageType(d.Id(), repo.PackageType, packageType); err != nil {
+			return diag.FromErr(err)
+		}
+
+		p := newPacker("artifactory_remote_"+packageType+"_repository", d)
+
+		packBaseRemoteRepo(repo, d, p)
+		p.set("package_type", repo.PackageType)
+		if pack != nil {
+			pack(repo, d, p)
+		}
+
+		return diag.FromErr(p.err())
+	}
+
+	resourceSchema := mergeSchema(baseRemoteRepoSchema(), remoteVerificationSchema(), packageTypeSchema(), extra)
+
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			repo := unpackRepo(d)
+			if diags := createRemoteRepository(ctx, c, d, repo); diags.HasError() {
+				return diags
+			}
+
+			return read(ctx, d, m)
+		},
+		ReadContext: read,
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			repo := unpackRepo(d)
+			if diags := updateRemoteRepository(ctx, c, d, repo); diags.HasError() {
+				return diags
+			}
+
+			return read(ctx, d, m)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			if diags := checkRepositoryDestroy(ctx, c, d, d.Id()+"-cache"); diags.HasError() {
+				return diags
+			}
+
+			resp, err := c.V1.Repositories.DeleteRemote(ctx, d.Id())
+			if repositoryNotFound(resp) {
+				return nil
+			}
+			return diag.FromErr(err)
+		},
+
+		CustomizeDiff: customdiff.All(
+			smartRemoteCustomizeDiff,
+			remoteVerificationCustomizeDiff,
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+// virtualRepositoryResource builds a package type specific virtual repository resource on top of the base virtual
+// schema. unpack and pack only need to handle the attributes in extra and may be nil if there are none
+func virtualRepositoryResource(packageType string, extra map[string]*schema.Schema,
+	unpack func(*ResourceData, *v1.VirtualRepository), pack func(*v1.VirtualRepository, *schema.ResourceData, *packer)) *schema.Resource {
+
+	unpackRepo := func(s *schema.ResourceData) *v1.VirtualRepository {
+		d := &ResourceData{s}
+		repo := new(v1.VirtualRepository)
+
+		unpackBaseVirtualRepo(d, repo)
+		repo.PackageType = artifactory.String(packageType)
+		if unpack != nil {
+			unpack(d, repo)
+		}
+
+		return repo
+	}
+
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		c := m.(*ArtClient)
+
+		repo, resp, err := c.V1.Repositories.GetVirtual(ctx, d.Id())
+		if repositoryNotFound(resp) {
+			d.SetId("")
+			return nil
+		} else if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := checkPackageType(d.Id(), repo.PackageType, packageType); err != nil {
+			return diag.FromErr(err)
+		}
+
+		p := newPacker("artifactory_virtual_"+packageType+"_repository", d)
+
+		packBaseVirtualRepo(repo, d, p)
+		p.set("package_type", repo.PackageType)
+		p.fail("resolved_repositories", packResolvedRepositories(ctx, c, repo, d))
+		if pack != nil {
+			pack(repo, d, p)
+		}
+
+		return diag.FromErr(p.err())
+	}
+
+	resourceSchema := mergeSchema(baseVirtualRepoSchema(), packageTypeSchema(), extra)
+
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			if diags := checkVirtualMembers(ctx, c, d, packageType); diags.HasError() {
+				return diags
+			}
+
+			repo := unpackRepo(d)
+			if _, err := c.V1.Repositories.CreateVirtual(ctx, repo); err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(*repo.Key)
+			return read(ctx, d, m)
+		},
+		ReadContext: read,
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			if diags := checkVirtualMembers(ctx, c, d, packageType); diags.HasError() {
+				return diags
+			}
+
+			repo := unpackRepo(d)
+			if _, err := c.V1.Repositories.UpdateVirtual(ctx, d.Id(), repo); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return read(ctx, d, m)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			c := m.(*ArtClient)
+
+			resp, err := c.V1.Repositories.DeleteVirtual(ctx, d.Id())
+			if repositoryNotFound(resp) {
+				return nil
+			}
+			return diag.FromErr(err)
+		},
+
+		CustomizeDiff: virtualMembersCustomizeDiff(packageType),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
+}
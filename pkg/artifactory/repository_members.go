@@ -0,0 +1,229 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+// repositorySummary is an entry of GET /api/repositories. go-artifactory's RepositoryDetails has no package type
+type repositorySummary struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	PackageType string `json:"packageType"`
+}
+
+func listRepositories(ctx context.Context, c *ArtClient) (map[string]repositorySummary, error) {
+	req, err := c.Raw.NewRequest(http.MethodGet, "/api/repositories", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []repositorySummary
+	if _, err := c.Raw.Do(ctx, req, &repos); err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %s", err)
+	}
+
+	result := make(map[string]repositorySummary, len(repos))
+	for _, repo := range repos {
+		result[repo.Key] = repo
+	}
+	return result, nil
+}
+
+// virtualMembers resolves the members of a virtual repository that is about to change. The planned members are used
+// for that repository, the server is asked for every other virtual repository
+type virtualMembers struct {
+	c       *ArtClient
+	repos   map[string]repositorySummary
+	key     string
+	members []string
+	nested  map[string][]string
+}
+
+func newVirtualMembers(ctx context.Context, c *ArtClient, key string, members []string) (*virtualMembers, error) {
+	repos, err := listRepositories(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return &virtualMembers{c: c, repos: repos, key: key, members: members, nested: map[string][]string{}}, nil
+}
+
+func (v *virtualMembers) membersOf(ctx context.Context, key string) ([]string, error) {
+	if key == v.key {
+		return v.members, nil
+	}
+	if members, ok := v.nested[key]; ok {
+		return members, nil
+	}
+
+	repo, _, err := v.c.V1.Repositories.GetVirtual(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read virtual repository %s: %s", key, err)
+	}
+
+	var members []string
+	if repo.Repositories != nil {
+		members = *repo.Repositories
+	}
+	v.nested[key] = members
+	return members, nil
+}
+
+// resolve flattens nested virtual repositories and returns the members in the order Artifactory resolves them: local
+// repositories first, then remote ones, each in the order they are listed. Members that don't exist are skipped.
+// A virtual repository that ends up containing itself is an error
+func (v *virtualMembers) resolve(ctx context.Context) ([]string, error) {
+	var locals, remotes []string
+	seen := map[string]bool{}
+
+	var visit func(key string, path []string) error
+	visit = func(key string, path []string) error {
+		path = append(append([]string{}, path...), key)
+		members, err := v.membersOf(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			if containsString(path, member) {
+				return fmt.Errorf("virtual repositories form a cycle: %s -> %s", strings.Join(path, " -> "), member)
+			}
+
+			repo, ok := v.repos[member]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case strings.EqualFold(repo.Type, "virtual"):
+				if err := visit(member, path); err != nil {
+					return err
+				}
+			case seen[member]:
+			case strings.EqualFold(repo.Type, "local"):
+				seen[member] = true
+				locals = append(locals, member)
+			case strings.EqualFold(repo.Type, "remote"):
+				seen[member] = true
+				remotes = append(remotes, member)
+			}
+		}
+		return nil
+	}
+
+	if err := visit(v.key, nil); err != nil {
+		return nil, err
+	}
+	return append(locals, remotes...), nil
+}
+
+// validate checks that the members share the package type of the virtual repository, that the default deployment
+// repository is a local member and that there are no cycles. Missing members are only reported when allowMissing is
+// false, since at plan time they may still be created in the same apply
+func (v *virtualMembers) validate(ctx context.Context, packageType, defaultDeploymentRepo string, allowMissing bool) error {
+	var errs []string
+
+	for _, member := range v.members {
+		repo, ok := v.repos[member]
+		if !ok {
+			if !allowMissing {
+				errs = append(errs, fmt.Sprintf("repository %s does not exist", member))
+			}
+			continue
+		}
+		if packageType != "" && !strings.EqualFold(repo.PackageType, packageType) {
+			errs = append(errs, fmt.Sprintf("repository %s has package type %s, expected %s", member, strings.ToLower(repo.PackageType), packageType))
+		}
+	}
+
+	if defaultDeploymentRepo != "" {
+		if !containsString(v.members, defaultDeploymentRepo) {
+			errs = append(errs, fmt.Sprintf("default_deployment_repo %s is not one of the repositories", defaultDeploymentRepo))
+		} else if repo, ok := v.repos[defaultDeploymentRepo]; ok && !strings.EqualFold(repo.Type, "local") {
+			errs = append(errs, fmt.Sprintf("default_deployment_repo %s is not a local repository", defaultDeploymentRepo))
+		}
+	}
+
+	if _, err := v.resolve(ctx); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid members for virtual repository %s:\n%s", v.key, strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func virtualMembersFromConfig(repositories interface{}) []string {
+	var members []string
+	for _, member := range repositories.([]interface{}) {
+		members = append(members, member.(string))
+	}
+	return members
+}
+
+// virtualMembersCustomizeDiff validates changed members against the server at plan time. It is skipped while the key,
+// package type or members still depend on other resources
+func virtualMembersCustomizeDiff(packageType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if !d.HasChange("repositories") && !d.HasChange("default_deployment_repo") {
+			return nil
+		}
+		if err := d.SetNewComputed("resolved_repositories"); err != nil {
+			return err
+		}
+
+		for _, key := range []string{"key", "repositories", "default_deployment_repo"} {
+			if !d.NewValueKnown(key) {
+				return nil
+			}
+		}
+		// the generic resource shares this func between all of its repositories, each has its own package type
+		pt := packageType
+		if pt == "" {
+			if !d.NewValueKnown("package_type") {
+				return nil
+			}
+			pt = d.Get("package_type").(string)
+		}
+
+		members, err := newVirtualMembers(ctx, m.(*ArtClient), d.Get("key").(string), virtualMembersFromConfig(d.Get("repositories")))
+		if err != nil {
+			return err
+		}
+		return members.validate(ctx, pt, d.Get("default_deployment_repo").(string), true)
+	}
+}
+
+// checkVirtualMembers is the apply time counterpart of virtualMembersCustomizeDiff, by now all members must exist
+func checkVirtualMembers(ctx context.Context, c *ArtClient, d *schema.ResourceData, packageType string) diag.Diagnostics {
+	members, err := newVirtualMembers(ctx, c, d.Get("key").(string), virtualMembersFromConfig(d.Get("repositories")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return attributeDiag("repositories", members.validate(ctx, packageType, d.Get("default_deployment_repo").(string), false))
+}
+
+func packResolvedRepositories(ctx context.Context, c *ArtClient, repo *v1.VirtualRepository, d *schema.ResourceData) error {
+	var members []string
+	if repo.Repositories != nil {
+		members = *repo.Repositories
+	}
+
+	v, err := newVirtualMembers(ctx, c, *repo.Key, members)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := v.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	return d.Set("resolved_repositories", resolved)
+}
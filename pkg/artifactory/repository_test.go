@@ -0,0 +1,206 @@
+package artifactory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// packageRepositoryTest creates a repository of a package type resource and updates it. Attributes are hcl
+// expressions, base is part of every step. Package types without attributes of their own update the description
+type packageRepositoryTest struct {
+	rclass      string
+	packageType string
+	base        map[string]string
+	create      map[string]string
+	update      map[string]string
+}
+
+var packageRepositoryTests = []packageRepositoryTest{
+	{
+		rclass: "local", packageType: "debian",
+		create: map[string]string{"debian_trivial_layout": "true"},
+		update: map[string]string{"debian_trivial_layout": "false"},
+	},
+	{
+		rclass: "local", packageType: "docker",
+		create: map[string]string{"max_unique_tags": "5", "docker_api_version": `"V2"`},
+		update: map[string]string{"max_unique_tags": "10", "docker_api_version": `"V2"`},
+	},
+	{
+		rclass: "local", packageType: "helm",
+		create: map[string]string{"description": `"helm repo"`},
+		update: map[string]string{"description": `"helm charts"`},
+	},
+	{
+		rclass: "local", packageType: "maven",
+		create: map[string]string{"handle_snapshots": "false", "max_unique_snapshots": "10", "snapshot_version_behavior": `"non-unique"`},
+		update: map[string]string{"handle_snapshots": "true", "max_unique_snapshots": "5", "snapshot_version_behavior": `"unique"`},
+	},
+	{
+		rclass: "local", packageType: "npm",
+		create: map[string]string{"description": `"npm repo"`},
+		update: map[string]string{"description": `"npm packages"`},
+	},
+	{
+		rclass: "local", packageType: "rpm",
+		create: map[string]string{"calculate_yum_metadata": "true", "yum_root_depth": "1"},
+		update: map[string]string{"calculate_yum_metadata": "true", "yum_root_depth": "2", "enable_file_lists_indexing": "true"},
+	},
+	{
+		rclass: "remote", packageType: "docker",
+		base:   map[string]string{"url": `"https://registry-1.docker.io/"`},
+		create: map[string]string{"enable_token_authentication": "true"},
+		update: map[string]string{"enable_token_authentication": "false"},
+	},
+	{
+		rclass: "remote", packageType: "helm",
+		base:   map[string]string{"url": `"https://charts.helm.sh/stable"`},
+		create: map[string]string{"description": `"helm repo"`},
+		update: map[string]string{"description": `"helm charts"`},
+	},
+	{
+		rclass: "remote", packageType: "maven",
+		base:   map[string]string{"url": `"https://repo1.maven.org/maven2/"`},
+		create: map[string]string{"fetch_jars_eagerly": "true", "reject_invalid_jars": "true"},
+		update: map[string]string{"fetch_sources_eagerly": "true", "reject_invalid_jars": "false"},
+	},
+	{
+		rclass: "remote", packageType: "npm",
+		base:   map[string]string{"url": `"https://registry.npmjs.org/"`},
+		create: map[string]string{"description": `"npm repo"`},
+		update: map[string]string{"description": `"npm packages"`},
+	},
+	{
+		rclass: "remote", packageType: "pypi",
+		base:   map[string]string{"url": `"https://files.pythonhosted.org"`},
+		create: map[string]string{"pypi_registry_url": `"https://pypi.org"`},
+		update: map[string]string{"pypi_registry_url": `"https://test.pypi.org"`},
+	},
+	{
+		rclass: "virtual", packageType: "docker",
+		base:   map[string]string{"repositories": "[]"},
+		create: map[string]string{"description": `"docker repo"`},
+		update: map[string]string{"description": `"docker images"`},
+	},
+	{
+		rclass: "virtual", packageType: "helm",
+		base:   map[string]string{"repositories": "[]"},
+		create: map[string]string{"virtual_retrieval_cache_period_seconds": "600"},
+		update: map[string]string{"virtual_retrieval_cache_period_seconds": "300"},
+	},
+	{
+		rclass: "virtual", packageType: "maven",
+		base:   map[string]string{"repositories": "[]"},
+		create: map[string]string{"pom_repository_references_cleanup_policy": `"nothing"`},
+		update: map[string]string{"pom_repository_references_cleanup_policy": `"discard_any_reference"`},
+	},
+	{
+		rclass: "virtual", packageType: "npm",
+		base:   map[string]string{"repositories": "[]"},
+		create: map[string]string{"external_dependencies_enabled": "true"},
+		update: map[string]string{"external_dependencies_enabled": "false"},
+	},
+}
+
+func (r packageRepositoryTest) resourceType() string {
+	return fmt.Sprintf("artifactory_%s_%s_repository", r.rclass, r.packageType)
+}
+
+func (r packageRepositoryTest) key() string {
+	return fmt.Sprintf("terraform-%s-%s-test-repo", r.rclass, r.packageType)
+}
+
+func (r packageRepositoryTest) config(attrs map[string]string) string {
+	all := map[string]string{"key": fmt.Sprintf("%q", r.key())}
+	for _, m := range []map[string]string{r.base, attrs} {
+		for k, v := range m {
+			all[k] = v
+		}
+	}
+
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := []string{fmt.Sprintf("resource %q %q {", r.resourceType(), r.key())}
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("\t%s = %s", k, all[k]))
+	}
+	return strings.Join(append(lines, "}"), "\n")
+}
+
+func (r packageRepositoryTest) check(attrs map[string]string) resource.TestCheckFunc {
+	id := r.resourceType() + "." + r.key()
+	checks := []resource.TestCheckFunc{
+		resource.TestCheckResourceAttr(id, "key", r.key()),
+		resource.TestCheckResourceAttr(id, "package_type", r.packageType),
+	}
+	for k, v := range attrs {
+		checks = append(checks, resource.TestCheckResourceAttr(id, k, strings.Trim(v, `"`)))
+	}
+	return resource.ComposeTestCheckFunc(checks...)
+}
+
+func (r packageRepositoryTest) checkDestroy() func(*terraform.State) error {
+	id := r.resourceType() + "." + r.key()
+	switch r.rclass {
+	case "local":
+		return resourceLocalRepositoryCheckDestroy(id)
+	case "remote":
+		return resourceRemoteRepositoryCheckDestroy(id)
+	}
+	return testAccCheckVirtualRepositoryDestroy(id)
+}
+
+func TestAccPackageRepositories(t *testing.T) {
+	for _, r := range packageRepositoryTests {
+		r := r
+		t.Run(r.resourceType(), func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				PreCheck:     func() { testAccPreCheck(t) },
+				CheckDestroy: r.checkDestroy(),
+				Providers:    testAccProviders,
+				Steps: []resource.TestStep{
+					{
+						Config: r.config(r.create),
+						Check:  r.check(r.create),
+					},
+					{
+						Config: r.config(r.update),
+						Check:  r.check(r.update),
+					},
+					{
+						ResourceName:      r.resourceType() + "." + r.key(),
+						ImportState:       true,
+						ImportStateVerify: true,
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestPackageRepositoryTests_attributes(t *testing.T) {
+	provider := Provider()
+	for _, r := range packageRepositoryTests {
+		res, ok := provider.ResourcesMap[r.resourceType()]
+		if !ok {
+			t.Errorf("%s is not a resource", r.resourceType())
+			continue
+		}
+		for _, attrs := range []map[string]string{r.base, r.create, r.update} {
+			for k := range attrs {
+				if _, ok := res.Schema[k]; !ok {
+					t.Errorf("%s has no attribute %s", r.resourceType(), k)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rickardl/go-artifactory/v2/artifactory/client"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+// remoteVerificationSchema holds the attributes controlling the connection check of remote repositories. They only
+// live in the state and are never sent to Artifactory
+func remoteVerificationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"verify_connection": {
+			Type:         schema.TypeBool,
+			Optional:     true,
+			Default:      false,
+			RequiredWith: []string{"verify_connection_path"},
+		},
+		"verify_connection_path": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"verify_connection_rollback": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+}
+
+// remoteVerificationCustomizeDiff rejects the repository root as path for the connection check, which the schema
+// requires along with verify_connection. The root can be answered from the cache or the listing of Artifactory without
+// going upstream, so it would pass with a broken url or credentials
+func remoteVerificationCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.Get("verify_connection").(bool) || !d.NewValueKnown("verify_connection_path") {
+		return nil
+	}
+	if strings.Trim(d.Get("verify_connection_path").(string), "/") == "" {
+		return fmt.Errorf("verify_connection_path must be set to a file of the upstream when verify_connection is enabled")
+	}
+	return nil
+}
+
+// verifyRemoteConnection expires the cached copy of path, then fetches it through the remote repository. That makes
+// Artifactory go upstream with the configured url, credentials and proxy
+func verifyRemoteConnection(ctx context.Context, c *ArtClient, key, path string) error {
+	path = strings.TrimPrefix(path, "/")
+
+	zap, err := c.Raw.NewRequest(http.MethodPost, fmt.Sprintf("/api/zap/%s/%s", key, path), nil)
+	if err != nil {
+		return err
+	}
+	// a path that isn't cached yet can't be expired
+	if resp, err := c.Raw.Do(ctx, zap, ioutil.Discard); err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return fmt.Errorf("connection check of remote repository %s failed to expire the cached %s: %s", key, path, err)
+	}
+
+	req, err := c.Raw.NewRequest(http.MethodGet, fmt.Sprintf("/%s/%s", key, path), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Raw.Do(ctx, req, ioutil.Discard)
+	if err == nil {
+		return nil
+	}
+
+	if errResp, ok := err.(*client.ErrorResponse); ok && resp != nil {
+		var messages []string
+		for _, e := range errResp.Errors {
+			messages = append(messages, e.Message)
+		}
+		if len(messages) == 0 {
+			messages = append(messages, http.StatusText(resp.StatusCode))
+		}
+		return fmt.Errorf("connection check of remote repository %s failed with upstream status %d fetching %s: %s", key, resp.StatusCode, path, strings.Join(messages, ", "))
+	}
+	return fmt.Errorf("connection check of remote repository %s failed: %s", key, err)
+}
+
+// createRemoteRepository creates the repository and, if asked to, checks its connection. A repository that fails the
+// check is deleted again when verify_connection_rollback is set, otherwise it is kept and terraform taints it
+func createRemoteRepository(ctx context.Context, c *ArtClient, d *schema.ResourceData, repo *v1.RemoteRepository) diag.Diagnostics {
+	if _, err := c.V1.Repositories.CreateRemote(ctx, repo); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(*repo.Key)
+
+	if !d.Get("verify_connection").(bool) {
+		return nil
+	}
+
+	err := verifyRemoteConnection(ctx, c, *repo.Key, d.Get("verify_connection_path").(string))
+	if err == nil || !d.Get("verify_connection_rollback").(bool) {
+		return attributeDiag("url", err)
+	}
+
+	if _, delErr := c.V1.Repositories.DeleteRemote(ctx, *repo.Key); delErr != nil {
+		return attributeDiag("url", fmt.Errorf("%s. Deleting the repository again failed as well: %s", err, delErr))
+	}
+	d.SetId("")
+	return attributeDiag("url", fmt.Errorf("%s. The repository was deleted again", err))
+}
+
+// updateRemoteRepository updates the repository and, if asked to, checks its connection. When the check fails and
+// verify_connection_rollback is set the previous configuration is restored, except for the password which Artifactory
+// only hands out encrypted
+func updateRemoteRepository(ctx context.Context, c *ArtClient, d *schema.ResourceData, repo *v1.RemoteRepository) diag.Diagnostics {
+	verify := d.Get("verify_connection").(bool)
+
+	var previous *v1.RemoteRepository
+	if verify && d.Get("verify_connection_rollback").(bool) {
+		var err error
+		if previous, _, err = c.V1.Repositories.GetRemote(ctx, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+		previous.Password = nil
+	}
+
+	if _, err := c.V1.Repositories.UpdateRemote(ctx, d.Id(), repo); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !verify {
+		return nil
+	}
+
+	err := verifyRemoteConnection(ctx, c, d.Id(), d.Get("verify_connection_path").(string))
+	if err == nil || previous == nil {
+		return attributeDiag("url", err)
+	}
+
+	if _, restoreErr := c.V1.Repositories.UpdateRemote(ctx, d.Id(), previous); restoreErr != nil {
+		return attributeDiag("url", fmt.Errorf("%s. Restoring the previous configuration failed as well: %s", err, restoreErr))
+	}
+	// keep the old values in the state, they are what the server has again
+	d.Partial(true)
+	return attributeDiag("url", fmt.Errorf("%s. The previous configuration was restored", err))
+}
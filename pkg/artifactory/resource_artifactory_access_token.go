@@ -0,0 +1,247 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rickardl/go-artifactory/v2/artifactory"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+// resourceArtifactoryAccessToken issues a token through /api/security/token. Tokens can't be changed, every argument
+// forces a new one, and it is replaced when it gets within renew_before of its expiry
+func resourceArtifactoryAccessToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAccessTokenCreate,
+		ReadContext:   resourceAccessTokenRead,
+		DeleteContext: resourceAccessTokenDelete,
+
+		CustomizeDiff: accessTokenCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"scope": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"groups"},
+			},
+			"groups": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ForceNew:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"scope"},
+			},
+			"expires_in": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      3600,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"renew_before": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      600,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"refreshable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"audience": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"access_token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"refresh_token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"token_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expiry": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// accessTokenClaims are the claims of the jwt artifactory issues that the resource uses
+type accessTokenClaims struct {
+	Id     string `json:"jti"`
+	Expiry int64  `json:"exp"`
+}
+
+func parseAccessToken(token string) (*accessTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("access token is not a jwt")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode access token: %s", err)
+	}
+
+	claims := new(accessTokenClaims)
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("failed to decode access token: %s", err)
+	}
+	return claims, nil
+}
+
+// accessTokenExpired tells whether a token expiring at expiry has to be replaced at now. Tokens that don't expire
+// have no expiry
+func accessTokenExpired(expiry string, renewBefore time.Duration, now time.Time) bool {
+	if expiry == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, expiry)
+	if err != nil {
+		return false
+	}
+	return !now.Add(renewBefore).Before(t)
+}
+
+func accessTokenCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	expiresIn, renewBefore := d.Get("expires_in").(int), d.Get("renew_before").(int)
+	if expiresIn > 0 && renewBefore >= expiresIn {
+		return fmt.Errorf("renew_before (%d) must be less than expires_in (%d), the token would be replaced on every plan", renewBefore, expiresIn)
+	}
+
+	if d.Id() == "" || !accessTokenExpired(d.Get("expiry").(string), time.Duration(renewBefore)*time.Second, time.Now()) {
+		return nil
+	}
+	if err := d.SetNewComputed("expiry"); err != nil {
+		return err
+	}
+	return d.ForceNew("expiry")
+}
+
+type tokenSummary struct {
+	TokenId string `json:"token_id"`
+}
+
+// listTokenIds returns the ids of the tokens that are still valid. Only admins may list tokens
+func listTokenIds(ctx context.Context, c *ArtClient) (map[string]bool, *http.Response, error) {
+	req, err := c.Raw.NewRequest(http.MethodGet, "/api/security/token", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tokens struct {
+		Tokens []tokenSummary `json:"tokens"`
+	}
+	resp, err := c.Raw.Do(ctx, req, &tokens)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	ids := make(map[string]bool, len(tokens.Tokens))
+	for _, t := range tokens.Tokens {
+		ids[t.TokenId] = true
+	}
+	return ids, resp, nil
+}
+
+func resourceAccessTokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	opts := &v1.AccessTokenOptions{
+		Username:    artifactory.String(d.Get("username").(string)),
+		ExpiresIn:   artifactory.Int(d.Get("expires_in").(int)),
+		Refreshable: artifactory.String(strconv.FormatBool(d.Get("refreshable").(bool))),
+	}
+	if v, ok := d.GetOk("scope"); ok {
+		opts.Scope = artifactory.String(v.(string))
+	}
+	if v, ok := d.GetOk("groups"); ok {
+		opts.Scope = artifactory.String("api:* member-of-groups:" + strings.Join(castToStringArr(v.(*schema.Set).List()), ","))
+	}
+	if v, ok := d.GetOk("audience"); ok {
+		opts.Audience = artifactory.String(v.(string))
+	}
+
+	token, _, err := c.V1.Security.CreateToken(ctx, opts)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	claims, err := parseAccessToken(*token.AccessToken)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(claims.Id)
+
+	p := newPacker("artifactory_access_token", d)
+	p.set("access_token", token.AccessToken)
+	p.set("refresh_token", token.RefreshToken)
+	p.set("token_type", token.TokenType)
+	// artifactory may normalize the scope, a configured one is kept as is so it doesn't force a new token
+	if _, ok := d.GetOk("scope"); !ok {
+		p.set("scope", token.Scope)
+	}
+	if claims.Expiry > 0 {
+		p.set("expiry", time.Unix(claims.Expiry, 0).UTC().Format(time.RFC3339))
+	}
+	return diag.FromErr(p.err())
+}
+
+// resourceAccessTokenRead only checks the token wasn't revoked or expired, tokens can't be read back
+func resourceAccessTokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	ids, resp, err := listTokenIds(ctx, c)
+	if resp != nil && resp.StatusCode == http.StatusForbidden {
+		log.Printf("[WARN] not allowed to list access tokens, can't tell whether token %s was revoked", d.Id())
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !ids[d.Id()] {
+		d.SetId("")
+	}
+	return nil
+}
+
+func resourceAccessTokenDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	_, resp, err := c.V1.Security.RevokeToken(ctx, v1.AccessTokenRevokeOptions{Token: d.Get("access_token").(string)})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return diag.FromErr(err)
+}
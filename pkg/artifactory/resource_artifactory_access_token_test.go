@@ -0,0 +1,86 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/stretchr/testify/assert"
+)
+
+const accessToken = `
+resource "artifactory_access_token" "ci" {
+  username   = "terraform-ci"
+  groups     = ["readers"]
+  expires_in = 1800
+}`
+
+func TestAccAccessToken(t *testing.T) {
+	const id = "artifactory_access_token.ci"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: accessToken,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(id, "access_token"),
+					resource.TestCheckResourceAttrSet(id, "expiry"),
+					resource.TestCheckResourceAttr(id, "scope", "api:* member-of-groups:readers"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseAccessToken(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"jfrt@01/users/ci","exp":1600000000,"jti":"4f1e"}`))
+	claims, err := parseAccessToken("eyJhbGciOiJSUzI1NiJ9." + payload + ".c2ln")
+	assert.NoError(t, err)
+	assert.Equal(t, &accessTokenClaims{Id: "4f1e", Expiry: 1600000000}, claims)
+
+	_, err = parseAccessToken("reference-token")
+	assert.EqualError(t, err, "access token is not a jwt")
+}
+
+func TestAccessTokenExpired(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, accessTokenExpired("", 10*time.Minute, now))
+	assert.False(t, accessTokenExpired("2021-06-01T12:30:00Z", 10*time.Minute, now))
+	assert.True(t, accessTokenExpired("2021-06-01T12:05:00Z", 10*time.Minute, now))
+	assert.True(t, accessTokenExpired("2021-06-01T11:00:00Z", 0, now))
+}
+
+func TestAccessTokenCreate_scope(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1600000000,"jti":"4f1e"}`))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "eyJhbGciOiJSUzI1NiJ9.%s.c2ln", "token_type": "Bearer", "scope": "member-of-groups:readers api:*"}`, payload)
+	}))
+	defer server.Close()
+
+	rt, err := artifactory.NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ArtClient{Artifactory: rt}
+
+	// a configured scope is kept as configured, the server's is only taken for groups
+	r := resourceArtifactoryAccessToken()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"username": "ci", "scope": "api:* member-of-groups:readers"})
+	assert.False(t, resourceAccessTokenCreate(context.Background(), d, c).HasError())
+	assert.Equal(t, "api:* member-of-groups:readers", d.Get("scope"))
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"username": "ci", "groups": []interface{}{"readers"}})
+	assert.False(t, resourceAccessTokenCreate(context.Background(), d, c).HasError())
+	assert.Equal(t, "member-of-groups:readers api:*", d.Get("scope"))
+}
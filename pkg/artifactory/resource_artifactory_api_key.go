@@ -0,0 +1,95 @@
+package artifactory
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// apiKeyId is the id of artifactory_api_key. /api/security/apiKey only handles the key of the user the provider
+// authenticates as, so there is a single key per provider
+const apiKeyId = "api_key"
+
+func resourceArtifactoryApiKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApiKeyCreate,
+		ReadContext:   resourceApiKeyRead,
+		UpdateContext: resourceApiKeyUpdate,
+		DeleteContext: resourceApiKeyDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"regenerate_version": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"api_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func resourceApiKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	current, _, err := c.V1.Security.GetApiKey(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if current.ApiKey != nil && *current.ApiKey != "" {
+		return diag.Errorf("the user already has an api key, import it with terraform import <address> %s", apiKeyId)
+	}
+
+	if _, _, err := c.V1.Security.CreateApiKey(ctx); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(apiKeyId)
+	return resourceApiKeyRead(ctx, d, m)
+}
+
+func resourceApiKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	key, _, err := c.V1.Security.GetApiKey(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if key.ApiKey == nil || *key.ApiKey == "" {
+		d.SetId("")
+		return nil
+	}
+
+	p := newPacker("artifactory_api_key", d)
+	p.set("api_key", key.ApiKey)
+	return diag.FromErr(p.err())
+}
+
+// resourceApiKeyUpdate regenerates the key, regenerate_version is the only attribute that can change
+func resourceApiKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	if _, _, err := c.V1.Security.RegenerateApiKey(ctx); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceApiKeyRead(ctx, d, m)
+}
+
+func resourceApiKeyDelete(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	_, resp, err := c.V1.Security.RevokeApiKey(ctx)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return diag.FromErr(err)
+}
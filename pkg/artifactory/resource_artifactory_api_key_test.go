@@ -0,0 +1,37 @@
+package artifactory
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const apiKey = `
+resource "artifactory_api_key" "test" {
+}`
+
+func TestAccApiKey(t *testing.T) {
+	const id = "artifactory_api_key.test"
+
+	if os.Getenv("ARTIFACTORY_API_KEY") != "" {
+		t.Skip("the provider authenticates with the api key the test would revoke")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: apiKey,
+				Check:  resource.TestCheckResourceAttrSet(id, "api_key"),
+			},
+			{
+				ResourceName:            id,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"regenerate_version"},
+			},
+		},
+	})
+}
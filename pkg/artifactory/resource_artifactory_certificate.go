@@ -9,21 +9,22 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
-	"github.com/hashicorp/terraform/helper/schema"
 )
 
 func resourceArtifactoryCertificate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCertificateCreate,
-		Read:   resourceCertificateRead,
-		Update: resourceCertificateUpdate,
-		Delete: resourceCertificateDelete,
-		Exists: resourceCertificateExists,
+		CreateContext: resourceCertificateCreate,
+		ReadContext:   resourceCertificateRead,
+		UpdateContext: resourceCertificateUpdate,
+		DeleteContext: resourceCertificateDelete,
+
+		SchemaVersion: 0,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -59,14 +60,13 @@ func resourceArtifactoryCertificate() *schema.Resource {
 			},
 		},
 
-		CustomizeDiff: func(d *schema.ResourceDiff, v interface{}) error {
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, v interface{}) error {
 			fingerprint, err := calculateFingerPrint(d.Get("content").(string))
 			if err != nil {
 				return err
 			}
 			if d.Get("fingerprint").(string) != fingerprint {
 				if err := d.SetNewComputed("fingerprint"); err != nil {
-					fmt.Println(err)
 					return err
 				}
 			}
@@ -115,10 +115,10 @@ func calculateFingerPrint(pemData string) (string, error) {
 	return formatFingerPrint(fingerprint[:]), nil
 }
 
-func findCertificate(d *schema.ResourceData, m interface{}) (*v1.CertificateDetails, error) {
-	c := m.(*artifactory.Artifactory)
+func findCertificate(ctx context.Context, d *schema.ResourceData, m interface{}) (*v1.CertificateDetails, error) {
+	c := m.(*ArtClient)
 
-	certs, _, err := c.V1.Security.GetCertificates(context.Background())
+	certs, _, err := c.V1.Security.GetCertificates(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -133,33 +133,28 @@ func findCertificate(d *schema.ResourceData, m interface{}) (*v1.CertificateDeta
 	return nil, nil
 }
 
-func resourceCertificateCreate(d *schema.ResourceData, m interface{}) error {
+func resourceCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("alias").(string))
-	return resourceCertificateUpdate(d, m)
+	return resourceCertificateUpdate(ctx, d, m)
 }
 
-func resourceCertificateRead(d *schema.ResourceData, m interface{}) error {
-	cert, err := findCertificate(d, m)
+func resourceCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	cert, err := findCertificate(ctx, d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if cert != nil {
-		hasErr := false
-		logErr := cascadingErr(&hasErr)
-
-		logErr(d.Set("alias", *cert.CertificateAlias))
-		logErr(d.Set("fingerprint", *cert.FingerPrint))
-		logErr(d.Set("issued_by", *cert.IssuedBy))
-		logErr(d.Set("issued_on", *cert.IssuedOn))
-		logErr(d.Set("issued_to", *cert.IssuedTo))
-		logErr(d.Set("valid_until", *cert.ValidUntil))
-
-		if hasErr {
-			return fmt.Errorf("failed to pack certificate")
-		}
+		p := newPacker("artifactory_certificate", d)
+
+		p.set("alias", *cert.CertificateAlias)
+		p.set("fingerprint", *cert.FingerPrint)
+		p.set("issued_by", *cert.IssuedBy)
+		p.set("issued_on", *cert.IssuedOn)
+		p.set("issued_to", *cert.IssuedTo)
+		p.set("valid_until", *cert.ValidUntil)
 
-		return nil
+		return diag.FromErr(p.err())
 	}
 
 	d.SetId("")
@@ -167,39 +162,26 @@ func resourceCertificateRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceCertificateUpdate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	_, _, err := c.V1.Security.AddCertificate(context.Background(), d.Id(), strings.NewReader(d.Get("content").(string)))
+	_, _, err := c.V1.Security.AddCertificate(ctx, d.Id(), strings.NewReader(d.Get("content").(string)))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceCertificateRead(d, m)
+	return resourceCertificateRead(ctx, d, m)
 }
 
-func resourceCertificateDelete(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	_, _, err := c.V1.Security.DeleteCertificate(context.Background(), d.Id())
+	_, _, err := c.V1.Security.DeleteCertificate(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
 
 	return nil
 }
-
-func resourceCertificateExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	cert, err := findCertificate(d, m)
-	if err != nil {
-		return false, err
-	}
-
-	if cert != nil {
-		return true, nil
-	}
-
-	return false, nil
-}
@@ -5,9 +5,8 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 const certificateFull = `
@@ -71,7 +70,7 @@ func TestAccCertificate_full(t *testing.T) {
 
 func testAccCheckCertificateDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 
 		if !ok {
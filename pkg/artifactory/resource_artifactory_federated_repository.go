@@ -0,0 +1,188 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rickardl/go-artifactory/v2/artifactory"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+// FederatedRepository is a local repository that is mirrored to the other members of the federation.
+// go-artifactory has no federated repositories yet, so they go through the raw client
+type FederatedRepository struct {
+	v1.LocalRepository
+	Members *[]FederatedMember `json:"members,omitempty"`
+}
+
+type FederatedMember struct {
+	Url     *string `json:"url,omitempty"`
+	Enabled *bool   `json:"enabled,omitempty"`
+}
+
+func resourceArtifactoryFederatedRepository() *schema.Resource {
+	local := resourceArtifactoryLocalRepository()
+
+	resourceSchema := mergeSchema(local.Schema, map[string]*schema.Schema{
+		"members": {
+			Type:     schema.TypeSet,
+			Required: true,
+			Set:      federatedMemberHash,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"url": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.NoZeroValues,
+					},
+					"enabled": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+				},
+			},
+		},
+	})
+
+	return &schema.Resource{
+		CreateContext: resourceFederatedRepositoryCreate,
+		ReadContext:   resourceFederatedRepositoryRead,
+		UpdateContext: resourceFederatedRepositoryUpdate,
+		DeleteContext: resourceFederatedRepositoryDelete,
+
+		CustomizeDiff: local.CustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+func federatedMemberHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return schema.HashString(fmt.Sprintf("%s-%t", m["url"].(string), m["enabled"].(bool)))
+}
+
+func unpackFederatedRepository(d *schema.ResourceData) *FederatedRepository {
+	repo := &FederatedRepository{LocalRepository: *unmarshalLocalRepository(d)}
+	repo.RClass = artifactory.String("federated")
+
+	// members are always sent in full, otherwise removing one would never reach the server
+	var members []FederatedMember
+	for _, raw := range d.Get("members").(*schema.Set).List() {
+		member := raw.(map[string]interface{})
+		members = append(members, FederatedMember{
+			Url:     artifactory.String(member["url"].(string)),
+			Enabled: artifactory.Bool(member["enabled"].(bool)),
+		})
+	}
+	repo.Members = &members
+
+	return repo
+}
+
+func packFederatedMembers(members *[]FederatedMember) *schema.Set {
+	set := schema.NewSet(federatedMemberHash, []interface{}{})
+	if members == nil {
+		return set
+	}
+
+	for _, member := range *members {
+		m := map[string]interface{}{"url": "", "enabled": true}
+		if member.Url != nil {
+			m["url"] = *member.Url
+		}
+		if member.Enabled != nil {
+			m["enabled"] = *member.Enabled
+		}
+		set.Add(m)
+	}
+	return set
+}
+
+func getFederatedRepository(ctx context.Context, c *ArtClient, key string) (*FederatedRepository, *http.Response, error) {
+	req, err := c.Raw.NewRequest(http.MethodGet, fmt.Sprintf("/api/repositories/%s", key), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo := new(FederatedRepository)
+	resp, err := c.Raw.Do(ctx, req, repo)
+	return repo, resp, err
+}
+
+func sendFederatedRepository(ctx context.Context, c *ArtClient, method string, repo *FederatedRepository) error {
+	req, err := c.Raw.NewJSONEncodedRequest(method, fmt.Sprintf("/api/repositories/%s", *repo.Key), repo)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Raw.Do(ctx, req, nil)
+	return err
+}
+
+func resourceFederatedRepositoryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	repo := unpackFederatedRepository(d)
+	if err := sendFederatedRepository(ctx, c, http.MethodPut, repo); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*repo.Key)
+	return resourceFederatedRepositoryRead(ctx, d, m)
+}
+
+func resourceFederatedRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	repo, resp, err := getFederatedRepository(ctx, c, d.Id())
+	if repositoryNotFound(resp) {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if repo.RClass != nil && *repo.RClass != "federated" {
+		return diag.Errorf("repository %s is a %s repository, not a federated one", d.Id(), *repo.RClass)
+	}
+
+	p := newPacker("artifactory_federated_repository", d)
+
+	packLocalRepository(&repo.LocalRepository, d, p)
+	p.set("members", packFederatedMembers(repo.Members))
+
+	return diag.FromErr(p.err())
+}
+
+func resourceFederatedRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	repo := unpackFederatedRepository(d)
+	if err := sendFederatedRepository(ctx, c, http.MethodPost, repo); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceFederatedRepositoryRead(ctx, d, m)
+}
+
+func resourceFederatedRepositoryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	if diags := checkRepositoryDestroy(ctx, c, d, d.Id()); diags.HasError() {
+		return diags
+	}
+
+	resp, err := c.V1.Repositories.DeleteLocal(ctx, d.Id())
+	if repositoryNotFound(resp) {
+		return nil
+	}
+	return diag.FromErr(err)
+}
@@ -0,0 +1,84 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/rickardl/go-artifactory/v2/artifactory"
+)
+
+const federatedRepositoryTemplate = `
+resource "artifactory_federated_repository" "terraform-federated-test-repo" {
+	key          = "terraform-federated-test-repo"
+	package_type = "maven"
+
+	members {
+		url     = "%s/terraform-federated-test-repo"
+		enabled = true
+	}
+}`
+
+func TestAccFederatedRepository_memberDrift(t *testing.T) {
+	const id = "artifactory_federated_repository.terraform-federated-test-repo"
+	config := fmt.Sprintf(federatedRepositoryTemplate, os.Getenv("ARTIFACTORY_URL"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: resourceFederatedRepositoryCheckDestroy(id),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "key", "terraform-federated-test-repo"),
+					resource.TestCheckResourceAttr(id, "package_type", "maven"),
+					resource.TestCheckResourceAttr(id, "members.#", "1"),
+				),
+			},
+			{
+				// a member added outside of terraform has to show up in the plan
+				PreConfig: func() {
+					c := testAccProvider.Meta().(*ArtClient)
+					repo, _, err := getFederatedRepository(context.Background(), c, "terraform-federated-test-repo")
+					if err != nil {
+						t.Fatal(err)
+					}
+					members := append(*repo.Members, FederatedMember{
+						Url:     artifactory.String("https://example.com/artifactory/terraform-federated-test-repo"),
+						Enabled: artifactory.Bool(false),
+					})
+					repo.Members = &members
+					if err := sendFederatedRepository(context.Background(), c, http.MethodPost, repo); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func resourceFederatedRepositoryCheckDestroy(id string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ArtClient)
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("err: Resource id[%s] not found", id)
+		}
+
+		_, resp, err := getFederatedRepository(context.Background(), client, rs.Primary.ID)
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error: Request failed: %s", err.Error())
+		}
+		return fmt.Errorf("error: Federated repository %s still exists", rs.Primary.ID)
+	}
+}
@@ -6,23 +6,26 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	ui "github.com/rickardl/go-artifactory/v2/artifactory/ui"
 )
 
 func resourceArtifactoryGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGroupCreate,
-		Read:   resourceGroupRead,
-		Update: resourceGroupUpdate,
-		Delete: resourceGroupDelete,
-		Exists: resourceGroupExists,
+		CreateContext: resourceGroupCreate,
+		ReadContext:   resourceGroupRead,
+		UpdateContext: resourceGroupUpdate,
+		DeleteContext: resourceGroupDelete,
+
+		CustomizeDiff: groupUserNamesCustomizeDiff,
+
+		SchemaVersion: 0,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -59,6 +62,7 @@ func resourceArtifactoryGroup() *schema.Resource {
 				Type:     schema.TypeList,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
+				Computed: true,
 			},
 		},
 	}
@@ -75,7 +79,7 @@ func unmarshalGroup(s *schema.ResourceData) (*ui.Group, error) {
 	group.AdminPrivileges = d.getBoolRef("admin_privileges", false)
 	group.Realm = d.getStringRef("realm", false)
 	group.RealmAttributes = d.getStringRef("realm_attributes", false)
-	group.UserNames = d.getListRef("userNames")
+	group.UserNames = d.getListRef("user_names")
 
 	// Validator
 	if group.AdminPrivileges != nil && group.AutoJoin != nil && *group.AdminPrivileges && *group.AutoJoin {
@@ -85,24 +89,23 @@ func unmarshalGroup(s *schema.ResourceData) (*ui.Group, error) {
 	return group, nil
 }
 
-func resourceGroupCreate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	group, err := unmarshalGroup(d)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	_, err = c.UI.Security.CreateGroup(context.Background(), group)
+	_, err = c.UI.Security.CreateGroup(ctx, group)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*group.Name)
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		c := m.(*artifactory.Artifactory)
-		_, resp, err := c.V1.Security.GetGroup(context.Background(), d.Id())
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, resp, err := c.V1.Security.GetGroup(ctx, d.Id())
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("error describing group: %s", err))
 		}
@@ -111,84 +114,76 @@ func resourceGroupCreate(d *schema.ResourceData, m interface{}) error {
 			return resource.RetryableError(fmt.Errorf("expected group to be created, but currently not found"))
 		}
 
-		return resource.NonRetryableError(resourceGroupRead(d, m))
+		return nil
 	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceGroupRead(ctx, d, m)
 }
 
-func resourceGroupRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	group, resp, err := c.UI.Security.GetGroup(context.Background(), d.Id())
+	group, resp, err := c.UI.Security.GetGroup(ctx, d.Id())
 
 	log.Printf("[DEBUG] Find Group: %v", d.Id())
 
 	// If we 404 it is likely the resources was externally deleted
 	// If the ID is updated to blank, this tells Terraform the resource no longer exist
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	} else if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	hasErr := false
-	logError := cascadingErr(&hasErr)
-	logError(d.Set("name", group.Name))
-	logError(d.Set("description", group.Description))
-	logError(d.Set("auto_join", group.AutoJoin))
-	logError(d.Set("admin_privileges", group.AdminPrivileges))
-	logError(d.Set("realm", group.Realm))
-	logError(d.Set("realm_attributes", group.RealmAttributes))
-	logError(d.Set("userNames", group.UserNames))
-
-	if hasErr {
-		return fmt.Errorf("failed to marshal group")
-	}
-	return nil
+	p := newPacker("artifactory_group", d)
+	p.set("name", group.Name)
+	p.set("description", group.Description)
+	p.set("auto_join", group.AutoJoin)
+	p.set("admin_privileges", group.AdminPrivileges)
+	p.set("realm", group.Realm)
+	p.set("realm_attributes", group.RealmAttributes)
+	p.set("user_names", group.UserNames)
+
+	return diag.FromErr(p.err())
 }
 
-func resourceGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 	group, err := unmarshalGroup(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	// Members may be managed by artifactory_group_members or artifactory_user instead
+	if !d.HasChange("user_names") {
+		group.UserNames = nil
 	}
-	_, err = c.UI.Security.UpdateGroup(context.Background(), d.Id(), group)
+
+	groupLocks.Lock(d.Id())
+	_, err = c.UI.Security.UpdateGroup(ctx, d.Id(), group)
+	groupLocks.Unlock(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*group.Name)
-	return resourceGroupRead(d, m)
+	return resourceGroupRead(ctx, d, m)
 }
 
-func resourceGroupDelete(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 	group, err := unmarshalGroup(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	_, resp, err := c.V1.Security.DeleteGroup(context.Background(), *group.Name)
+	_, resp, err := c.V1.Security.DeleteGroup(ctx, *group.Name)
 
-	if err != nil && resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		return nil
 	}
 
-	return err
-}
-
-func resourceGroupExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	c := m.(*artifactory.Artifactory)
-
-	groupName := d.Id()
-	_, resp, err := c.V1.Security.GetGroup(context.Background(), groupName)
-
-	if resp.StatusCode == http.StatusNotFound {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	}
-
-	return true, nil
+	return diag.FromErr(err)
 }
@@ -0,0 +1,276 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceArtifactoryGroupMembers manages the members of a group. Unless authoritative, members added elsewhere are
+// left alone
+func resourceArtifactoryGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupMembersCreate,
+		ReadContext:   resourceGroupMembersRead,
+		UpdateContext: resourceGroupMembersUpdate,
+		DeleteContext: resourceGroupMembersDelete,
+
+		CustomizeDiff: groupMembersCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"members": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"authoritative": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+// membershipClaims remembers which resources of a plan manage the members of a group. A claim on all members, by
+// artifactory_group.user_names or authoritative group members, conflicts with any other claim on the group, including
+// an identical one of another resource
+type membershipClaims struct {
+	lock   sync.Mutex
+	claims map[string][]membershipClaim
+}
+
+type membershipClaim struct {
+	owner string
+	all   bool
+}
+
+func newMembershipClaims() *membershipClaims {
+	return &membershipClaims{claims: map[string][]membershipClaim{}}
+}
+
+// claim records that owner manages members of group, it fails when an earlier claim conflicts with it. Every call is
+// a claim of its own, only a claim on some members of the group is recorded once per owner
+func (m *membershipClaims) claim(group, owner string, all bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	claim := membershipClaim{owner: owner, all: all}
+	var conflicts []string
+	recorded := false
+	for _, other := range m.claims[group] {
+		recorded = recorded || !all && other == claim
+		if all || other.all {
+			conflicts = append(conflicts, other.owner)
+		}
+	}
+	if !recorded {
+		m.claims[group] = append(m.claims[group], claim)
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("members of group %s are managed by %s and %s, they would overwrite each other. Manage them in one place only",
+			group, owner, strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+// claimGroupMembers claims group for owner in the current plan. The sdk diffs a resource that requires a new one
+// twice, the second time without its configuration, so only the first diff claims
+func claimGroupMembers(d *schema.ResourceDiff, m interface{}, group, owner string, all bool) error {
+	if d.GetRawConfig().IsNull() {
+		return nil
+	}
+	if c, ok := m.(*ArtClient); ok && c.memberships != nil {
+		return c.memberships.claim(group, owner, all)
+	}
+	return nil
+}
+
+// configured tells whether key is set in the configuration rather than taken from the state
+func configured(d *schema.ResourceDiff, key string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	return !config.GetAttr(key).IsNull()
+}
+
+func groupMembersCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("group") {
+		return nil
+	}
+	owner := "artifactory_group_members"
+	if d.Get("authoritative").(bool) {
+		owner = "authoritative artifactory_group_members"
+	}
+	return claimGroupMembers(d, m, d.Get("group").(string), owner, d.Get("authoritative").(bool))
+}
+
+func groupUserNamesCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("name") || !configured(d, "user_names") {
+		return nil
+	}
+	return claimGroupMembers(d, m, d.Get("name").(string), "artifactory_group.user_names", true)
+}
+
+func userGroupsCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("name") || !d.NewValueKnown("groups") || !configured(d, "groups") {
+		return nil
+	}
+	for _, group := range d.Get("groups").(*schema.Set).List() {
+		owner := fmt.Sprintf("artifactory_user.groups of %s", d.Get("name"))
+		if err := claimGroupMembers(d, m, group.(string), owner, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateGroupMembers removes and adds members of a group. Groups are read and written as a whole, the lock keeps
+// concurrent changes of this provider from overwriting each other
+func updateGroupMembers(ctx context.Context, c *ArtClient, name string, update func(members map[string]bool)) error {
+	groupLocks.Lock(name)
+	defer groupLocks.Unlock(name)
+
+	group, _, err := c.UI.Security.GetGroup(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to read group %s: %s", name, err)
+	}
+
+	members := map[string]bool{}
+	if group.UserNames != nil {
+		for _, user := range *group.UserNames {
+			members[user] = true
+		}
+	}
+	update(members)
+
+	userNames := make([]string, 0, len(members))
+	for user := range members {
+		userNames = append(userNames, user)
+	}
+	sort.Strings(userNames)
+	group.UserNames = &userNames
+
+	if _, err := c.UI.Security.UpdateGroup(ctx, name, group); err != nil {
+		return fmt.Errorf("failed to update members of group %s: %s", name, err)
+	}
+	return nil
+}
+
+func resourceGroupMembersCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	name := d.Get("group").(string)
+	members := castToStringArr(d.Get("members").(*schema.Set).List())
+	authoritative := d.Get("authoritative").(bool)
+
+	err := updateGroupMembers(ctx, c, name, func(current map[string]bool) {
+		if authoritative {
+			for user := range current {
+				delete(current, user)
+			}
+		}
+		for _, user := range members {
+			current[user] = true
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	return resourceGroupMembersRead(ctx, d, m)
+}
+
+func resourceGroupMembersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	group, resp, err := c.UI.Security.GetGroup(ctx, d.Id())
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var current []string
+	if group.UserNames != nil {
+		current = *group.UserNames
+	}
+
+	// Only the members managed here are kept, unless authoritative or imported
+	members := schema.NewSet(schema.HashString, castToInterfaceArr(current))
+	if managed := d.Get("members").(*schema.Set); !d.Get("authoritative").(bool) && managed.Len() > 0 {
+		members = members.Intersection(managed)
+	}
+
+	p := newPacker("artifactory_group_members", d)
+	p.set("group", d.Id())
+	p.set("members", members)
+	return diag.FromErr(p.err())
+}
+
+func resourceGroupMembersUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	o, n := d.GetChange("members")
+	removed := castToStringArr(o.(*schema.Set).Difference(n.(*schema.Set)).List())
+	members := castToStringArr(n.(*schema.Set).List())
+	authoritative := d.Get("authoritative").(bool)
+
+	err := updateGroupMembers(ctx, c, d.Id(), func(current map[string]bool) {
+		if authoritative {
+			for user := range current {
+				delete(current, user)
+			}
+		}
+		for _, user := range removed {
+			delete(current, user)
+		}
+		for _, user := range members {
+			current[user] = true
+		}
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGroupMembersRead(ctx, d, m)
+}
+
+func resourceGroupMembersDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	_, resp, err := c.UI.Security.GetGroup(ctx, d.Id())
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	members := castToStringArr(d.Get("members").(*schema.Set).List())
+	return diag.FromErr(updateGroupMembers(ctx, c, d.Id(), func(current map[string]bool) {
+		for _, user := range members {
+			delete(current, user)
+		}
+	}))
+}
@@ -0,0 +1,123 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const groupMembers = `
+resource "artifactory_group" "test-group" {
+  name = "terraform-group-members"
+}
+
+resource "artifactory_user" "test-user" {
+  name     = "terraform-group-member"
+  email    = "member@example.com"
+}
+
+resource "artifactory_group_members" "test-members" {
+  group   = artifactory_group.test-group.name
+  members = [artifactory_user.test-user.name]
+}`
+
+func TestAccGroupMembers(t *testing.T) {
+	const id = "artifactory_group_members.test-members"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckGroupDestroy("artifactory_group.test-group"),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: groupMembers,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "members.#", "1"),
+					resource.TestCheckResourceAttr(id, "authoritative", "false"),
+				),
+			},
+			{
+				ResourceName:            id,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"authoritative"},
+			},
+		},
+	})
+}
+
+func TestMembershipClaims(t *testing.T) {
+	claims := newMembershipClaims()
+
+	assert.NoError(t, claims.claim("readers", "artifactory_user.groups of jane", false))
+	assert.NoError(t, claims.claim("readers", "artifactory_group_members", false))
+	assert.NoError(t, claims.claim("readers", "artifactory_group_members", false))
+	assert.EqualError(t, claims.claim("readers", "artifactory_group.user_names", true),
+		"members of group readers are managed by artifactory_group.user_names and artifactory_group_members, "+
+			"artifactory_user.groups of jane, they would overwrite each other. Manage them in one place only")
+	assert.Error(t, claims.claim("readers", "artifactory_user.groups of joe", false))
+	assert.NoError(t, claims.claim("deployers", "artifactory_group.user_names", true))
+	assert.Error(t, claims.claim("deployers", "artifactory_group.user_names", true))
+	assert.NoError(t, claims.claim("admins", "authoritative artifactory_group_members", true))
+	assert.EqualError(t, claims.claim("admins", "authoritative artifactory_group_members", true),
+		"members of group admins are managed by authoritative artifactory_group_members and authoritative "+
+			"artifactory_group_members, they would overwrite each other. Manage them in one place only")
+}
+
+// planGroupMembers diffs config the way terraform plans a new resource, with the configuration as raw config
+func planGroupMembers(t *testing.T, config map[string]interface{}, c *ArtClient) error {
+	t.Helper()
+
+	r := resourceArtifactoryGroupMembers()
+	js, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := ctyjson.Unmarshal(js, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.Diff(context.Background(), &terraform.InstanceState{RawConfig: value}, terraform.NewResourceConfigRaw(config), c)
+	return err
+}
+
+func TestGroupMembersCustomizeDiff_conflict(t *testing.T) {
+	c := &ArtClient{memberships: newMembershipClaims()}
+
+	assert.NoError(t, planGroupMembers(t, map[string]interface{}{
+		"group":   "deployers",
+		"members": []interface{}{"jane"},
+	}, c))
+
+	assert.Error(t, planGroupMembers(t, map[string]interface{}{
+		"group":         "deployers",
+		"members":       []interface{}{"joe"},
+		"authoritative": true,
+	}, c))
+
+	// claims belong to one configured provider
+	assert.NoError(t, planGroupMembers(t, map[string]interface{}{
+		"group":         "deployers",
+		"members":       []interface{}{"joe"},
+		"authoritative": true,
+	}, &ArtClient{memberships: newMembershipClaims()}))
+}
+
+func TestGroupMembersCustomizeDiff_authoritativeTwice(t *testing.T) {
+	c := &ArtClient{memberships: newMembershipClaims()}
+	config := map[string]interface{}{
+		"group":         "admins",
+		"members":       []interface{}{"jane"},
+		"authoritative": true,
+	}
+
+	// a new resource is diffed twice by the sdk, it must not conflict with itself
+	assert.NoError(t, planGroupMembers(t, config, c))
+	assert.Error(t, planGroupMembers(t, config, c), "a second resource with the same members")
+}
@@ -0,0 +1,177 @@
+package artifactory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Strategies of ldap group settings. The descriptor keeps them upper case, they are sent and read back as such
+var ldapGroupStrategies = []string{"static", "dynamic", "hierarchical"}
+
+type ldapGroupSetting struct {
+	Name                 string `xml:"name" json:"-"`
+	EnabledLdap          string `xml:"enabledLdap" json:"enabledLdap"`
+	Strategy             string `xml:"strategy" json:"strategy"`
+	GroupBaseDn          string `xml:"groupBaseDn" json:"groupBaseDn"`
+	GroupNameAttribute   string `xml:"groupNameAttribute" json:"groupNameAttribute"`
+	GroupMemberAttribute string `xml:"groupMemberAttribute" json:"groupMemberAttribute"`
+	Filter               string `xml:"filter" json:"filter"`
+	SubTree              bool   `xml:"subTree" json:"subTree"`
+	DescriptionAttribute string `xml:"descriptionAttribute" json:"descriptionAttribute"`
+}
+
+func resourceArtifactoryLdapGroupSetting() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLdapGroupSettingCreate,
+		ReadContext:   resourceLdapGroupSettingRead,
+		UpdateContext: resourceLdapGroupSettingUpdate,
+		DeleteContext: resourceLdapGroupSettingDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"ldap_setting_key": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"strategy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "static",
+				ValidateFunc: validation.StringInSlice(ldapGroupStrategies, false),
+			},
+			"group_base_dn": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"group_name_attribute": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "cn",
+			},
+			"group_member_attribute": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "uniqueMember",
+			},
+			"filter": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "(objectClass=groupOfNames)",
+			},
+			"sub_tree": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"description_attribute": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "description",
+			},
+		},
+	}
+}
+
+func unpackLdapGroupSetting(d *schema.ResourceData) ldapGroupSetting {
+	return ldapGroupSetting{
+		Name:                 d.Get("name").(string),
+		EnabledLdap:          d.Get("ldap_setting_key").(string),
+		Strategy:             strings.ToUpper(d.Get("strategy").(string)),
+		GroupBaseDn:          d.Get("group_base_dn").(string),
+		GroupNameAttribute:   d.Get("group_name_attribute").(string),
+		GroupMemberAttribute: d.Get("group_member_attribute").(string),
+		Filter:               d.Get("filter").(string),
+		SubTree:              d.Get("sub_tree").(bool),
+		DescriptionAttribute: d.Get("description_attribute").(string),
+	}
+}
+
+func packLdapGroupSetting(setting ldapGroupSetting, d *schema.ResourceData) error {
+	p := newPacker("artifactory_ldap_group_setting", d)
+
+	p.set("name", setting.Name)
+	p.set("ldap_setting_key", setting.EnabledLdap)
+	p.set("strategy", strings.ToLower(setting.Strategy))
+	p.set("group_base_dn", setting.GroupBaseDn)
+	p.set("group_name_attribute", setting.GroupNameAttribute)
+	p.set("group_member_attribute", setting.GroupMemberAttribute)
+	p.set("filter", setting.Filter)
+	p.set("sub_tree", setting.SubTree)
+	p.set("description_attribute", setting.DescriptionAttribute)
+
+	return p.err()
+}
+
+func putLdapGroupSetting(ctx context.Context, c *ArtClient, setting ldapGroupSetting) error {
+	return patchSystemConfiguration(ctx, c, securityPatch("ldapGroupSettings", setting.Name, setting))
+}
+
+func resourceLdapGroupSettingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	setting := unpackLdapGroupSetting(d)
+
+	// patching would silently take over an existing setting
+	config, err := getSystemConfiguration(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, existing := range config.Security.LdapGroupSettings {
+		if existing.Name == setting.Name {
+			return diag.Errorf("ldap group setting %s already exists, import it to manage it with terraform", setting.Name)
+		}
+	}
+
+	if err := putLdapGroupSetting(ctx, c, setting); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(setting.Name)
+	return resourceLdapGroupSettingRead(ctx, d, m)
+}
+
+func resourceLdapGroupSettingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	config, err := getSystemConfiguration(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, setting := range config.Security.LdapGroupSettings {
+		if setting.Name == d.Id() {
+			return diag.FromErr(packLdapGroupSetting(setting, d))
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceLdapGroupSettingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	if err := putLdapGroupSetting(ctx, c, unpackLdapGroupSetting(d)); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceLdapGroupSettingRead(ctx, d, m)
+}
+
+func resourceLdapGroupSettingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	return diag.FromErr(patchSystemConfiguration(ctx, c, securityPatch("ldapGroupSettings", d.Id(), nil)))
+}
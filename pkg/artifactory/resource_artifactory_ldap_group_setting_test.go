@@ -0,0 +1,76 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const ldapGroupSettingConfig = `
+resource "artifactory_ldap_setting" "test" {
+	key           = "ldap-group-test"
+	url           = "ldap://ldap.example.com:389/dc=example,dc=com"
+	search_filter = "(uid={0})"
+}
+
+resource "artifactory_ldap_group_setting" "test" {
+	name             = "ldap-group-test"
+	ldap_setting_key = artifactory_ldap_setting.test.key
+	strategy         = "dynamic"
+	group_base_dn    = "ou=groups"
+	filter           = "(objectClass=groupOfUniqueNames)"
+}`
+
+func TestAccLdapGroupSetting(t *testing.T) {
+	const id = "artifactory_ldap_group_setting.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: ldapGroupSettingConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "ldap_setting_key", "ldap-group-test"),
+					resource.TestCheckResourceAttr(id, "strategy", "dynamic"),
+					resource.TestCheckResourceAttr(id, "group_base_dn", "ou=groups"),
+					resource.TestCheckResourceAttr(id, "group_member_attribute", "uniqueMember"),
+				),
+			},
+			{
+				ResourceName:      id,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnpackLdapGroupSetting(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceArtifactoryLdapGroupSetting().Schema, map[string]interface{}{
+		"name":             "groups",
+		"ldap_setting_key": "ldap1",
+		"strategy":         "dynamic",
+		"group_base_dn":    "ou=groups",
+	})
+
+	patch, err := json.Marshal(securityPatch("ldapGroupSettings", "groups", unpackLdapGroupSetting(d)))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"security": {"ldapGroupSettings": {"groups": {
+		"enabledLdap":          "ldap1",
+		"strategy":             "DYNAMIC",
+		"groupBaseDn":          "ou=groups",
+		"groupNameAttribute":   "cn",
+		"groupMemberAttribute": "uniqueMember",
+		"filter":               "(objectClass=groupOfNames)",
+		"subTree":              true,
+		"descriptionAttribute": "description"
+	}}}}`, string(patch))
+
+	// the state keeps the strategy as configured
+	assert.NoError(t, packLdapGroupSetting(unpackLdapGroupSetting(d), d))
+	assert.Equal(t, "dynamic", d.Get("strategy"))
+}
@@ -0,0 +1,216 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ldapSetting is read from the xml config descriptor and patched as yaml, through json. The manager password is
+// encrypted in the descriptor, so it is never read
+type ldapSetting struct {
+	Key                      string            `xml:"key" json:"-"`
+	Enabled                  bool              `xml:"enabled" json:"enabled"`
+	LdapUrl                  string            `xml:"ldapUrl" json:"ldapUrl"`
+	UserDnPattern            string            `xml:"userDnPattern" json:"userDnPattern"`
+	Search                   ldapSettingSearch `xml:"search" json:"search"`
+	AutoCreateUser           bool              `xml:"autoCreateUser" json:"autoCreateUser"`
+	EmailAttribute           string            `xml:"emailAttribute" json:"emailAttribute"`
+	LdapPoisoningProtection  bool              `xml:"ldapPoisoningProtection" json:"ldapPoisoningProtection"`
+	AllowUserToAccessProfile bool              `xml:"allowUserToAccessProfile" json:"allowUserToAccessProfile"`
+	PagingSupportEnabled     bool              `xml:"pagingSupportEnabled" json:"pagingSupportEnabled"`
+}
+
+type ldapSettingSearch struct {
+	SearchFilter    string  `xml:"searchFilter" json:"searchFilter"`
+	SearchBase      string  `xml:"searchBase" json:"searchBase"`
+	SearchSubTree   bool    `xml:"searchSubTree" json:"searchSubTree"`
+	ManagerDn       string  `xml:"managerDn" json:"managerDn"`
+	ManagerPassword *string `xml:"-" json:"managerPassword,omitempty"`
+}
+
+func resourceArtifactoryLdapSetting() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLdapSettingCreate,
+		ReadContext:   resourceLdapSettingRead,
+		UpdateContext: resourceLdapSettingUpdate,
+		DeleteContext: resourceLdapSettingDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"ldap", "ldaps"}),
+			},
+			"user_dn_pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"search_filter": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"search_base": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"search_sub_tree": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"manager_dn": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"manager_password":         secretSchema(),
+			"manager_password_version": secretVersionSchema(),
+			"email_attribute": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "mail",
+			},
+			"auto_create_user": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"ldap_poisoning_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"allow_user_to_access_profile": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"paging_support_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func unpackLdapSetting(s *schema.ResourceData) ldapSetting {
+	d := &ResourceData{s}
+	return ldapSetting{
+		Key:           d.Get("key").(string),
+		Enabled:       d.Get("enabled").(bool),
+		LdapUrl:       d.Get("url").(string),
+		UserDnPattern: d.Get("user_dn_pattern").(string),
+		Search: ldapSettingSearch{
+			SearchFilter:    d.Get("search_filter").(string),
+			SearchBase:      d.Get("search_base").(string),
+			SearchSubTree:   d.Get("search_sub_tree").(bool),
+			ManagerDn:       d.Get("manager_dn").(string),
+			ManagerPassword: d.getSecretRef("manager_password"),
+		},
+		AutoCreateUser:           d.Get("auto_create_user").(bool),
+		EmailAttribute:           d.Get("email_attribute").(string),
+		LdapPoisoningProtection:  d.Get("ldap_poisoning_protection").(bool),
+		AllowUserToAccessProfile: d.Get("allow_user_to_access_profile").(bool),
+		PagingSupportEnabled:     d.Get("paging_support_enabled").(bool),
+	}
+}
+
+func packLdapSetting(setting ldapSetting, d *schema.ResourceData) error {
+	p := newPacker("artifactory_ldap_setting", d)
+
+	p.set("key", setting.Key)
+	p.set("enabled", setting.Enabled)
+	p.set("url", setting.LdapUrl)
+	p.set("user_dn_pattern", setting.UserDnPattern)
+	p.set("search_filter", setting.Search.SearchFilter)
+	p.set("search_base", setting.Search.SearchBase)
+	p.set("search_sub_tree", setting.Search.SearchSubTree)
+	p.set("manager_dn", setting.Search.ManagerDn)
+	p.set("manager_password", stateSecretDigest(d, "manager_password"))
+	p.set("email_attribute", setting.EmailAttribute)
+	p.set("auto_create_user", setting.AutoCreateUser)
+	p.set("ldap_poisoning_protection", setting.LdapPoisoningProtection)
+	p.set("allow_user_to_access_profile", setting.AllowUserToAccessProfile)
+	p.set("paging_support_enabled", setting.PagingSupportEnabled)
+
+	return p.err()
+}
+
+func putLdapSetting(ctx context.Context, c *ArtClient, setting ldapSetting) error {
+	return patchSystemConfiguration(ctx, c, securityPatch("ldapSettings", setting.Key, setting))
+}
+
+func resourceLdapSettingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	setting := unpackLdapSetting(d)
+
+	// patching would silently take over an existing setting
+	config, err := getSystemConfiguration(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, existing := range config.Security.LdapSettings {
+		if existing.Key == setting.Key {
+			return diag.Errorf("ldap setting %s already exists, import it to manage it with terraform", setting.Key)
+		}
+	}
+
+	if err := putLdapSetting(ctx, c, setting); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(setting.Key)
+	return resourceLdapSettingRead(ctx, d, m)
+}
+
+func resourceLdapSettingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	config, err := getSystemConfiguration(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, setting := range config.Security.LdapSettings {
+		if setting.Key == d.Id() {
+			return diag.FromErr(packLdapSetting(setting, d))
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceLdapSettingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	if err := putLdapSetting(ctx, c, unpackLdapSetting(d)); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceLdapSettingRead(ctx, d, m)
+}
+
+func resourceLdapSettingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	return diag.FromErr(patchSystemConfiguration(ctx, c, securityPatch("ldapSettings", d.Id(), nil)))
+}
@@ -0,0 +1,158 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/rickardl/go-artifactory/v2/artifactory/client"
+	"github.com/stretchr/testify/assert"
+)
+
+const ldapSettingConfig = `
+resource "artifactory_ldap_setting" "test" {
+	key              = "ldap-test"
+	url              = "ldap://ldap.example.com:389/dc=example,dc=com"
+	search_filter    = "(uid={0})"
+	search_base      = "ou=people"
+	manager_dn       = "cn=admin,dc=example,dc=com"
+	manager_password = "s3cret"
+	email_attribute  = "mail"
+	auto_create_user = false
+}`
+
+func TestAccLdapSetting(t *testing.T) {
+	const id = "artifactory_ldap_setting.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: ldapSettingConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "url", "ldap://ldap.example.com:389/dc=example,dc=com"),
+					resource.TestCheckResourceAttr(id, "search_filter", "(uid={0})"),
+					resource.TestCheckResourceAttr(id, "auto_create_user", "false"),
+					resource.TestCheckResourceAttr(id, "manager_password", secretDigest("ldap-test", 0, "s3cret")),
+				),
+			},
+			{
+				ResourceName:            id,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"manager_password"},
+			},
+		},
+	})
+}
+
+func TestParseSystemConfiguration(t *testing.T) {
+	config, err := parseSystemConfiguration(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<config xmlns="http://artifactory.jfrog.org/xsd/2.2.5">
+    <security>
+        <ldapSettings>
+            <ldapSetting>
+                <key>ldap1</key>
+                <enabled>true</enabled>
+                <ldapUrl>ldap://ldap.example.com/dc=example,dc=com</ldapUrl>
+                <search>
+                    <searchFilter>(uid={0})</searchFilter>
+                    <searchBase>ou=people</searchBase>
+                    <searchSubTree>true</searchSubTree>
+                    <managerDn>cn=admin</managerDn>
+                    <managerPassword>JE2kd0b0XFZn1v</managerPassword>
+                </search>
+                <autoCreateUser>true</autoCreateUser>
+                <emailAttribute>mail</emailAttribute>
+            </ldapSetting>
+        </ldapSettings>
+        <ldapGroupSettings>
+            <ldapGroupSetting>
+                <name>groups</name>
+                <groupBaseDn>ou=groups</groupBaseDn>
+                <groupNameAttribute>cn</groupNameAttribute>
+                <groupMemberAttribute>uniqueMember</groupMemberAttribute>
+                <subTree>true</subTree>
+                <filter>(objectClass=groupOfNames)</filter>
+                <strategy>STATIC</strategy>
+                <enabledLdap>ldap1</enabledLdap>
+            </ldapGroupSetting>
+        </ldapGroupSettings>
+    </security>
+</config>`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ldapSetting{{
+		Key:     "ldap1",
+		Enabled: true,
+		LdapUrl: "ldap://ldap.example.com/dc=example,dc=com",
+		Search: ldapSettingSearch{
+			SearchFilter:  "(uid={0})",
+			SearchBase:    "ou=people",
+			SearchSubTree: true,
+			ManagerDn:     "cn=admin",
+		},
+		AutoCreateUser: true,
+		EmailAttribute: "mail",
+	}}, config.Security.LdapSettings)
+	assert.Equal(t, []ldapGroupSetting{{
+		Name:                 "groups",
+		EnabledLdap:          "ldap1",
+		Strategy:             "STATIC",
+		GroupBaseDn:          "ou=groups",
+		GroupNameAttribute:   "cn",
+		GroupMemberAttribute: "uniqueMember",
+		Filter:               "(objectClass=groupOfNames)",
+		SubTree:              true,
+	}}, config.Security.LdapGroupSettings)
+}
+
+func TestLdapSettingCreate_existing(t *testing.T) {
+	patched := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			patched = true
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<config><security>
+			<ldapSettings><ldapSetting><key>ldap1</key></ldapSetting></ldapSettings>
+			<ldapGroupSettings><ldapGroupSetting><name>groups</name></ldapGroupSetting></ldapGroupSettings>
+		</security></config>`)
+	}))
+	defer server.Close()
+
+	rt, err := artifactory.NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := client.NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ArtClient{Artifactory: rt, Raw: raw}
+
+	d := schema.TestResourceDataRaw(t, resourceArtifactoryLdapSetting().Schema, map[string]interface{}{
+		"key": "ldap1",
+		"url": "ldap://ldap.example.com",
+	})
+	diags := resourceLdapSettingCreate(context.Background(), d, c)
+	assert.True(t, diags.HasError())
+	assert.Equal(t, "ldap setting ldap1 already exists, import it to manage it with terraform", diags[0].Summary)
+
+	d = schema.TestResourceDataRaw(t, resourceArtifactoryLdapGroupSetting().Schema, map[string]interface{}{
+		"name":             "groups",
+		"ldap_setting_key": "ldap1",
+	})
+	diags = resourceLdapGroupSettingCreate(context.Background(), d, c)
+	assert.True(t, diags.HasError())
+	assert.Equal(t, "ldap group setting groups already exists, import it to manage it with terraform", diags[0].Summary)
+
+	assert.False(t, patched)
+}
@@ -0,0 +1,20 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryLocalDebianRepository() *schema.Resource {
+	return localRepositoryResource("debian", map[string]*schema.Schema{
+		"debian_trivial_layout": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}, func(d *ResourceData, repo *v1.LocalRepository) {
+		repo.DebianTrivialLayout = d.getBoolRef("debian_trivial_layout", true)
+	}, func(repo *v1.LocalRepository, d *schema.ResourceData, p *packer) {
+		p.set("debian_trivial_layout", repo.DebianTrivialLayout)
+	})
+}
@@ -0,0 +1,30 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryLocalDockerRepository() *schema.Resource {
+	return localRepositoryResource("docker", map[string]*schema.Schema{
+		"max_unique_tags": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"docker_api_version": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "V2",
+			ValidateFunc: validation.StringInSlice([]string{"V1", "V2"}, false),
+		},
+	}, func(d *ResourceData, repo *v1.LocalRepository) {
+		repo.MaxUniqueTags = d.getIntRef("max_unique_tags", true)
+		repo.DockerApiVersion = d.getStringRef("docker_api_version", true)
+	}, func(repo *v1.LocalRepository, d *schema.ResourceData, p *packer) {
+		p.set("max_unique_tags", repo.MaxUniqueTags)
+		p.set("docker_api_version", repo.DockerApiVersion)
+	})
+}
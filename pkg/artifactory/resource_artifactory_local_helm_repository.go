@@ -0,0 +1,9 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceArtifactoryLocalHelmRepository() *schema.Resource {
+	return localRepositoryResource("helm", nil, nil, nil)
+}
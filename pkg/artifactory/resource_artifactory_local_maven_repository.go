@@ -0,0 +1,66 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryLocalMavenRepository() *schema.Resource {
+	return localRepositoryResource("maven", map[string]*schema.Schema{
+		"handle_releases": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"handle_snapshots": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"max_unique_snapshots": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"checksum_policy_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "client-checksums",
+			ValidateFunc: validation.StringInSlice([]string{
+				"client-checksums",
+				"server-generated-checksums",
+			}, false),
+		},
+		"snapshot_version_behavior": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "unique",
+			ValidateFunc: validation.StringInSlice([]string{
+				"unique",
+				"non-unique",
+				"deployer",
+			}, false),
+		},
+		"suppress_pom_consistency_checks": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}, func(d *ResourceData, repo *v1.LocalRepository) {
+		repo.HandleReleases = d.getBoolRef("handle_releases", true)
+		repo.HandleSnapshots = d.getBoolRef("handle_snapshots", true)
+		repo.MaxUniqueSnapshots = d.getIntRef("max_unique_snapshots", true)
+		repo.ChecksumPolicyType = d.getStringRef("checksum_policy_type", true)
+		repo.SnapshotVersionBehavior = d.getStringRef("snapshot_version_behavior", true)
+		repo.SuppressPomConsistencyChecks = d.getBoolRef("suppress_pom_consistency_checks", true)
+	}, func(repo *v1.LocalRepository, d *schema.ResourceData, p *packer) {
+		p.set("handle_releases", repo.HandleReleases)
+		p.set("handle_snapshots", repo.HandleSnapshots)
+		p.set("max_unique_snapshots", repo.MaxUniqueSnapshots)
+		p.set("checksum_policy_type", repo.ChecksumPolicyType)
+		p.set("snapshot_version_behavior", repo.SnapshotVersionBehavior)
+		p.set("suppress_pom_consistency_checks", repo.SuppressPomConsistencyChecks)
+	})
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/rickardl/go-artifactory/v2/artifactory"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
 )
 
 const localRepositoryBasic = `
@@ -37,18 +39,17 @@ func TestAccLocalRepository_basic(t *testing.T) {
 const localRepositoryConfigFull = `
 resource "artifactory_local_repository" "terraform-local-test-repo-full" {
     key                             = "terraform-local-test-repo-full"
-    package_type                    = "npm"
+    package_type                    = "maven"
 	description                     = "Test repo for terraform-provider-artifactory"
 	notes                           = "Test repo for terraform-provider-artifactory"
 	includes_pattern                = "**/*"
-	excludes_pattern                = "**/*.tgz"
-	repo_layout_ref                 = "npm-default"
+	excludes_pattern                = "**/*.war"
+	repo_layout_ref                 = "maven-2-default"
 	handle_releases                 = true
 	handle_snapshots                = true
 	max_unique_snapshots            = 25
 	debian_trivial_layout           = false
 	checksum_policy_type            = "client-checksums"
-	max_unique_tags                 = 100
 	snapshot_version_behavior       = "unique"
 	suppress_pom_consistency_checks = true
 	blacked_out                     = false
@@ -56,7 +57,6 @@ resource "artifactory_local_repository" "terraform-local-test-repo-full" {
 	archive_browsing_enabled        = false
 	calculate_yum_metadata          = false
 	yum_root_depth                  = 0
-	docker_api_version              = "V2"
 }`
 
 func TestAccLocalRepository_full(t *testing.T) {
@@ -69,18 +69,17 @@ func TestAccLocalRepository_full(t *testing.T) {
 				Config: localRepositoryConfigFull,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "key", "terraform-local-test-repo-full"),
-					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "package_type", "npm"),
+					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "package_type", "maven"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "description", "Test repo for terraform-provider-artifactory"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "notes", "Test repo for terraform-provider-artifactory"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "includes_pattern", "**/*"),
-					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "excludes_pattern", "**/*.tgz"),
-					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "repo_layout_ref", "npm-default"),
+					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "excludes_pattern", "**/*.war"),
+					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "repo_layout_ref", "maven-2-default"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "handle_releases", "true"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "handle_snapshots", "true"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "max_unique_snapshots", "25"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "debian_trivial_layout", "false"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "checksum_policy_type", "client-checksums"),
-					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "max_unique_tags", "100"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "snapshot_version_behavior", "unique"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "suppress_pom_consistency_checks", "true"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "blacked_out", "false"),
@@ -89,7 +88,117 @@ func TestAccLocalRepository_full(t *testing.T) {
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "archive_browsing_enabled", "false"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "calculate_yum_metadata", "false"),
 					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "yum_root_depth", "0"),
-					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-full", "docker_api_version", "V2"),
+				),
+			},
+		},
+	})
+}
+
+const localRepositoryForceDestroy = `
+resource "artifactory_local_repository" "terraform-local-test-repo-force-destroy" {
+	key 	      = "terraform-local-test-repo-force-destroy"
+	package_type  = "generic"
+	force_destroy = true
+}`
+
+func TestAccLocalRepository_forceDestroy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: resourceLocalRepositoryCheckDestroy("artifactory_local_repository.terraform-local-test-repo-force-destroy"),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: localRepositoryForceDestroy,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-force-destroy", "key", "terraform-local-test-repo-force-destroy"),
+					resource.TestCheckResourceAttr("artifactory_local_repository.terraform-local-test-repo-force-destroy", "force_destroy", "true"),
+				),
+			},
+		},
+	})
+}
+
+const localRepositoryWrongPackageTypeAttribute = `
+resource "artifactory_local_repository" "terraform-local-test-repo-wrong-attribute" {
+	key 	           = "terraform-local-test-repo-wrong-attribute"
+	package_type       = "npm"
+	docker_api_version = "V2"
+}`
+
+const localRepositoryUnknownPackageType = `
+resource "artifactory_local_repository" "terraform-local-test-repo-unknown-type" {
+	key 	     = "terraform-local-test-repo-unknown-type"
+	package_type = "nonsense"
+}`
+
+func TestAccLocalRepository_packageTypeValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      localRepositoryWrongPackageTypeAttribute,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("docker_api_version only applies to package types docker"),
+			},
+			{
+				Config:      localRepositoryUnknownPackageType,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`package_type "nonsense" is not supported`),
+			},
+		},
+	})
+}
+
+const localRepositoryUpdateBefore = `
+resource "artifactory_local_repository" "terraform-local-test-repo-update" {
+	key 	     = "terraform-local-test-repo-update"
+	package_type = "nuget"
+	description  = "Before"
+}`
+
+const localRepositoryUpdateAfter = `
+resource "artifactory_local_repository" "terraform-local-test-repo-update" {
+	key 	     = "terraform-local-test-repo-update"
+	package_type = "nuget"
+	description  = "After"
+}`
+
+func TestAccLocalRepository_updateKeepsUnmanagedSettings(t *testing.T) {
+	const id = "artifactory_local_repository.terraform-local-test-repo-update"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: resourceLocalRepositoryCheckDestroy(id),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: localRepositoryUpdateBefore,
+				Check:  resource.TestCheckResourceAttr(id, "description", "Before"),
+			},
+			{
+				// Change a setting terraform does not manage behind its back
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*ArtClient)
+					repo := &v1.LocalRepository{ForceNugetAuthentication: artifactory.Bool(true)}
+					if _, err := client.V1.Repositories.UpdateLocal(context.Background(), "terraform-local-test-repo-update", repo); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: localRepositoryUpdateAfter,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "description", "After"),
+					func(s *terraform.State) error {
+						client := testAccProvider.Meta().(*ArtClient)
+						repo, _, err := client.V1.Repositories.GetLocal(context.Background(), s.RootModule().Resources[id].Primary.ID)
+						if err != nil {
+							return err
+						}
+						if repo.ForceNugetAuthentication == nil || !*repo.ForceNugetAuthentication {
+							return fmt.Errorf("error: update reset force_nuget_authentication on %s", *repo.Key)
+						}
+						return nil
+					},
 				),
 			},
 		},
@@ -98,7 +207,7 @@ func TestAccLocalRepository_full(t *testing.T) {
 
 func resourceLocalRepositoryCheckDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 
 		if !ok {
@@ -116,3 +225,16 @@ func resourceLocalRepositoryCheckDestroy(id string) func(*terraform.State) error
 		}
 	}
 }
+
+func TestLocalRepository_stateUpgradeV0(t *testing.T) {
+	testStateUpgrade(t, resourceArtifactoryLocalRepository(), 0, map[string]interface{}{
+		"id":           "terraform-local-test-repo",
+		"key":          "terraform-local-test-repo",
+		"package_type": "generic",
+	}, map[string]interface{}{
+		"id":            "terraform-local-test-repo",
+		"key":           "terraform-local-test-repo",
+		"package_type":  "generic",
+		"force_destroy": false,
+	})
+}
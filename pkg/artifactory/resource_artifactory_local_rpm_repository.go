@@ -0,0 +1,36 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryLocalRpmRepository() *schema.Resource {
+	return localRepositoryResource("rpm", map[string]*schema.Schema{
+		"calculate_yum_metadata": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"yum_root_depth": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"enable_file_lists_indexing": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}, func(d *ResourceData, repo *v1.LocalRepository) {
+		repo.CalculateYumMetadata = d.getBoolRef("calculate_yum_metadata", true)
+		repo.YumRootDepth = d.getIntRef("yum_root_depth", true)
+		repo.EnableFileListsIndexing = d.getBoolRef("enable_file_lists_indexing", true)
+	}, func(repo *v1.LocalRepository, d *schema.ResourceData, p *packer) {
+		p.set("calculate_yum_metadata", repo.CalculateYumMetadata)
+		p.set("yum_root_depth", repo.YumRootDepth)
+		p.set("enable_file_lists_indexing", repo.EnableFileListsIndexing)
+	})
+}
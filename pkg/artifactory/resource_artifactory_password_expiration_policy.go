@@ -0,0 +1,117 @@
+package artifactory
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// passwordExpirationPolicyId is the id of artifactory_password_expiration_policy, the policy is global
+const passwordExpirationPolicyId = "password_expiration_policy"
+
+const passwordExpirationPolicyPath = "/api/security/configuration/passwordExpirationPolicy"
+
+// passwordExpirationPolicy is sent through the raw client, go-artifactory omits the booleans when they are false so
+// the policy could never be disabled
+type passwordExpirationPolicy struct {
+	Enabled        bool `json:"enabled"`
+	PasswordMaxAge int  `json:"passwordMaxAge"`
+	NotifyByEmail  bool `json:"notifyByEmail"`
+}
+
+func resourceArtifactoryPasswordExpirationPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePasswordExpirationPolicyUpdate,
+		ReadContext:   resourcePasswordExpirationPolicyRead,
+		UpdateContext: resourcePasswordExpirationPolicyUpdate,
+		DeleteContext: resourcePasswordExpirationPolicyDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"password_max_age": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"notify_by_email": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func setPasswordExpirationPolicy(ctx context.Context, c *ArtClient, policy passwordExpirationPolicy) error {
+	req, err := c.Raw.NewJSONEncodedRequest(http.MethodPut, passwordExpirationPolicyPath, policy)
+	if err != nil {
+		return err
+	}
+	_, err = c.Raw.Do(ctx, req, nil)
+	return err
+}
+
+func getPasswordExpirationPolicy(ctx context.Context, c *ArtClient) (passwordExpirationPolicy, error) {
+	policy := passwordExpirationPolicy{}
+
+	req, err := c.Raw.NewRequest(http.MethodGet, passwordExpirationPolicyPath, nil)
+	if err != nil {
+		return policy, err
+	}
+	_, err = c.Raw.Do(ctx, req, &policy)
+	return policy, err
+}
+
+func resourcePasswordExpirationPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	policy, err := getPasswordExpirationPolicy(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	p := newPacker("artifactory_password_expiration_policy", d)
+	p.set("enabled", policy.Enabled)
+	p.set("password_max_age", policy.PasswordMaxAge)
+	p.set("notify_by_email", policy.NotifyByEmail)
+	return diag.FromErr(p.err())
+}
+
+func resourcePasswordExpirationPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	err := setPasswordExpirationPolicy(ctx, c, passwordExpirationPolicy{
+		Enabled:        d.Get("enabled").(bool),
+		PasswordMaxAge: d.Get("password_max_age").(int),
+		NotifyByEmail:  d.Get("notify_by_email").(bool),
+	})
+	if err != nil {
+		return diag.Errorf("failed to set the password expiration policy: %s", err)
+	}
+
+	d.SetId(passwordExpirationPolicyId)
+	return resourcePasswordExpirationPolicyRead(ctx, d, m)
+}
+
+// resourcePasswordExpirationPolicyDelete disables expiry, the policy itself can't be removed
+func resourcePasswordExpirationPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	return diag.FromErr(setPasswordExpirationPolicy(ctx, c, passwordExpirationPolicy{
+		Enabled:        false,
+		PasswordMaxAge: d.Get("password_max_age").(int),
+		NotifyByEmail:  d.Get("notify_by_email").(bool),
+	}))
+}
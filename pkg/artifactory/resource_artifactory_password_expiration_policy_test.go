@@ -0,0 +1,43 @@
+package artifactory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const passwordExpirationPolicyConfig = `
+resource "artifactory_password_expiration_policy" "test" {
+	enabled          = %t
+	password_max_age = 90
+	notify_by_email  = false
+}`
+
+func TestAccPasswordExpirationPolicy(t *testing.T) {
+	const id = "artifactory_password_expiration_policy.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(passwordExpirationPolicyConfig, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "enabled", "true"),
+					resource.TestCheckResourceAttr(id, "password_max_age", "90"),
+					resource.TestCheckResourceAttr(id, "notify_by_email", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(passwordExpirationPolicyConfig, false),
+				Check:  resource.TestCheckResourceAttr(id, "enabled", "false"),
+			},
+			{
+				ResourceName:      id,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -3,15 +3,26 @@ package artifactory
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"net/http"
+	"strings"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/helper/validation"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	v2 "github.com/rickardl/go-artifactory/v2/artifactory/v2"
 )
 
+// PermissionTarget adds the release bundle section go-artifactory's v2 permission target doesn't have yet,
+// so permission targets go through the raw client
+type PermissionTarget struct {
+	v2.PermissionTarget
+	ReleaseBundle *v2.Permission `json:"releaseBundle,omitempty"`
+}
+
+// Permissions of the v2 api
+var permissionsV2 = []string{v2.PERM_READ, v2.PERM_ANNOTATE, v2.PERM_WRITE, v2.PERM_DELETE, v2.PERM_MANAGE}
+
 func resourceArtifactoryPermissionTarget() *schema.Resource {
 
 	actionSchema := schema.Schema{
@@ -27,14 +38,8 @@ func resourceArtifactoryPermissionTarget() *schema.Resource {
 				"permissions": {
 					Type: schema.TypeSet,
 					Elem: &schema.Schema{
-						Type: schema.TypeString,
-						ValidateFunc: validation.StringInSlice([]string{
-							v2.PERM_READ,
-							v2.PERM_ANNOTATE,
-							v2.PERM_WRITE,
-							v2.PERM_DELETE,
-							v2.PERM_MANAGE,
-						}, false),
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice(permissionsV2, false),
 					},
 					Set:      schema.HashString,
 					Required: true,
@@ -85,14 +90,17 @@ func resourceArtifactoryPermissionTarget() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourcePermissionTargetCreate,
-		Read:   resourcePermissionTargetRead,
-		Update: resourcePermissionTargetUpdate,
-		Delete: resourcePermissionTargetDelete,
-		Exists: resourcePermissionTargetExists,
+		CreateContext: resourcePermissionTargetCreate,
+		ReadContext:   resourcePermissionTargetRead,
+		UpdateContext: resourcePermissionTargetUpdate,
+		DeleteContext: resourcePermissionTargetDelete,
+
+		CustomizeDiff: permissionTargetCustomizeDiff,
+
+		SchemaVersion: 0,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -101,13 +109,19 @@ func resourceArtifactoryPermissionTarget() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
-			"repo":  &principalSchema,
-			"build": &principalSchema,
+			"repo":           &principalSchema,
+			"build":          &principalSchema,
+			"release_bundle": &principalSchema,
+			"validate_principals": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
 
-func unpackPermissionTarget(s *schema.ResourceData) *v2.PermissionTarget {
+func unpackPermissionTarget(s *schema.ResourceData) *PermissionTarget {
 	d := &ResourceData{s}
 
 	unpackPermission := func(rawPermissionData interface{}) *v2.Permission {
@@ -172,7 +186,7 @@ func unpackPermissionTarget(s *schema.ResourceData) *v2.PermissionTarget {
 		return permission
 	}
 
-	pTarget := new(v2.PermissionTarget)
+	pTarget := new(PermissionTarget)
 
 	pTarget.Name = d.getStringRef("name", false)
 
@@ -184,92 +198,142 @@ func unpackPermissionTarget(s *schema.ResourceData) *v2.PermissionTarget {
 		pTarget.Build = unpackPermission(v)
 	}
 
+	if v, ok := d.GetOk("release_bundle"); ok {
+		pTarget.ReleaseBundle = unpackPermission(v)
+	}
+
 	return pTarget
 }
 
-func packPermissionTarget(permissionTarget *v2.PermissionTarget, d *schema.ResourceData) error {
-	packPermission := func(p *v2.Permission) []interface{} {
-		packPermMap := func(e map[string][]string) []interface{} {
-			perm := make([]interface{}, len(e))
+// packPermission turns a section of a permission target into its schema representation
+func packPermission(p *v2.Permission) []interface{} {
+	packPermMap := func(e map[string][]string) []interface{} {
+		perm := make([]interface{}, len(e))
 
-			count := 0
-			for k, v := range e {
-				perm[count] = map[string]interface{}{
-					"name":        k,
-					"permissions": schema.NewSet(schema.HashString, castToInterfaceArr(v)),
-				}
-				count++
+		count := 0
+		for k, v := range e {
+			perm[count] = map[string]interface{}{
+				"name":        k,
+				"permissions": schema.NewSet(schema.HashString, castToInterfaceArr(v)),
 			}
+			count++
+		}
+
+		return perm
+	}
+
+	s := map[string]interface{}{}
+
+	if p != nil {
+		if p.IncludePatterns != nil {
+			s["includes_pattern"] = schema.NewSet(schema.HashString, castToInterfaceArr(*p.IncludePatterns))
+		}
+
+		if p.ExcludePatterns != nil {
+			s["excludes_pattern"] = schema.NewSet(schema.HashString, castToInterfaceArr(*p.ExcludePatterns))
+		}
 
-			return perm
+		if p.Repositories != nil {
+			s["repositories"] = schema.NewSet(schema.HashString, castToInterfaceArr(*p.Repositories))
 		}
 
-		s := map[string]interface{}{}
+		if p.Actions != nil {
+			perms := make(map[string]interface{})
 
-		if p != nil {
-			if p.IncludePatterns != nil {
-				s["includes_pattern"] = schema.NewSet(schema.HashString, castToInterfaceArr(*p.IncludePatterns))
+			if p.Actions.Users != nil {
+				perms["users"] = schema.NewSet(hashPrincipal, packPermMap(*p.Actions.Users))
 			}
 
-			if p.ExcludePatterns != nil {
-				s["excludes_pattern"] = schema.NewSet(schema.HashString, castToInterfaceArr(*p.ExcludePatterns))
+			if p.Actions.Groups != nil {
+				perms["groups"] = schema.NewSet(hashPrincipal, packPermMap(*p.Actions.Groups))
 			}
 
-			if p.Repositories != nil {
-				s["repositories"] = schema.NewSet(schema.HashString, castToInterfaceArr(*p.Repositories))
+			if len(perms) > 0 {
+				s["actions"] = []interface{}{perms}
 			}
+		}
+	}
 
-			if p.Actions != nil {
-				perms := make(map[string]interface{})
+	return []interface{}{s}
+}
 
-				if p.Actions.Users != nil {
-					perms["users"] = schema.NewSet(hashPrincipal, packPermMap(*p.Actions.Users))
-				}
+func packPermissionTarget(permissionTarget *PermissionTarget, d *schema.ResourceData) error {
+	p := newPacker("artifactory_permission_target", d)
 
-				if p.Actions.Groups != nil {
-					perms["groups"] = schema.NewSet(hashPrincipal, packPermMap(*p.Actions.Groups))
-				}
+	p.set("name", permissionTarget.Name)
+	if permissionTarget.Repo != nil {
+		p.set("repo", packPermission(permissionTarget.Repo))
+	}
+	if permissionTarget.Build != nil {
+		p.set("build", packPermission(permissionTarget.Build))
+	}
+	if permissionTarget.ReleaseBundle != nil {
+		p.set("release_bundle", packPermission(permissionTarget.ReleaseBundle))
+	}
 
-				if len(perms) > 0 {
-					s["actions"] = []interface{}{perms}
-				}
-			}
-		}
+	return p.err()
+}
 
-		return []interface{}{s}
+func getPermissionTarget(ctx context.Context, c *ArtClient, name string) (*PermissionTarget, *http.Response, error) {
+	req, err := c.Raw.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/security/permissions/%s", name), nil)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	hasErr := false
-	logErrors := cascadingErr(&hasErr)
+	permissionTarget := new(PermissionTarget)
+	resp, err := c.Raw.Do(ctx, req, permissionTarget)
+	return permissionTarget, resp, err
+}
 
-	logErrors(d.Set("name", permissionTarget.Name))
-	if permissionTarget.Repo != nil {
-		logErrors(d.Set("repo", packPermission(permissionTarget.Repo)))
+func sendPermissionTarget(ctx context.Context, c *ArtClient, method string, permissionTarget *PermissionTarget) (*http.Response, error) {
+	req, err := c.Raw.NewJSONEncodedRequest(method, fmt.Sprintf("/api/v2/security/permissions/%s", *permissionTarget.Name), permissionTarget)
+	if err != nil {
+		return nil, err
 	}
-	if permissionTarget.Build != nil {
-		logErrors(d.Set("build", packPermission(permissionTarget.Build)))
+
+	return c.Raw.Do(ctx, req, nil)
+}
+
+// checkReleaseBundleRepositories makes sure the repositories of the release bundle section exist, artifactory
+// silently drops unknown ones. The special keywords are not repositories
+func checkReleaseBundleRepositories(ctx context.Context, c *ArtClient, permissionTarget *PermissionTarget) diag.Diagnostics {
+	if permissionTarget.ReleaseBundle == nil || permissionTarget.ReleaseBundle.Repositories == nil {
+		return nil
 	}
 
-	if hasErr {
-		return fmt.Errorf("failed to marshal permission target")
+	repos, err := listRepositories(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var missing []string
+	for _, key := range *permissionTarget.ReleaseBundle.Repositories {
+		if _, ok := repos[key]; !ok && !containsString(specialRepositories, key) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return attributeDiag("release_bundle.0.repositories", fmt.Errorf("release bundle repositories %s do not exist", strings.Join(missing, ", ")))
 	}
 	return nil
 }
 
-func resourcePermissionTargetCreate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourcePermissionTargetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	permissionTarget := unpackPermissionTarget(d)
+	if diags := checkReleaseBundleRepositories(ctx, c, permissionTarget); diags.HasError() {
+		return diags
+	}
 
-	_, err := c.V2.Security.CreatePermissionTarget(context.Background(), *permissionTarget.Name, permissionTarget)
+	_, err := sendPermissionTarget(ctx, c, http.MethodPost, permissionTarget)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*permissionTarget.Name)
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		c := m.(*artifactory.Artifactory)
-		exists, err := c.V2.Security.HasPermissionTarget(context.Background(), d.Id())
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		exists, err := c.V2.Security.HasPermissionTarget(ctx, d.Id())
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("error describing permssions target: %s", err))
 		}
@@ -278,50 +342,55 @@ func resourcePermissionTargetCreate(d *schema.ResourceData, m interface{}) error
 			return resource.RetryableError(fmt.Errorf("expected permission target to be created, but currently not found"))
 		}
 
-		return resource.NonRetryableError(resourcePermissionTargetRead(d, m))
+		return nil
 	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourcePermissionTargetRead(ctx, d, m)
 }
 
-func resourcePermissionTargetRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourcePermissionTargetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	permissionTarget, resp, err := c.V2.Security.GetPermissionTarget(context.Background(), d.Id())
-	if resp.StatusCode == http.StatusNotFound {
+	permissionTarget, resp, err := getPermissionTarget(ctx, c, d.Id())
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	} else if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return packPermissionTarget(permissionTarget, d)
+	return diag.FromErr(packPermissionTarget(permissionTarget, d))
 }
 
-func resourcePermissionTargetUpdate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourcePermissionTargetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	permissionTarget := unpackPermissionTarget(d)
-	if _, err := c.V2.Security.UpdatePermissionTarget(context.Background(), d.Id(), permissionTarget); err != nil {
-		return err
+	if diags := checkReleaseBundleRepositories(ctx, c, permissionTarget); diags.HasError() {
+		return diags
+	}
+
+	permissionTargetLocks.Lock(d.Id())
+	_, err := sendPermissionTarget(ctx, c, http.MethodPut, permissionTarget)
+	permissionTargetLocks.Unlock(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*permissionTarget.Name)
-	return resourcePermissionTargetRead(d, m)
+	return resourcePermissionTargetRead(ctx, d, m)
 }
 
-func resourcePermissionTargetDelete(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourcePermissionTargetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	permissionTarget := unpackPermissionTarget(d)
-	resp, err := c.V2.Security.DeletePermissionTarget(context.Background(), *permissionTarget.Name)
+	resp, err := c.V2.Security.DeletePermissionTarget(ctx, *permissionTarget.Name)
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		return nil
 	}
-	return err
-}
-
-func resourcePermissionTargetExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	c := m.(*artifactory.Artifactory)
-
-	return c.V2.Security.HasPermissionTarget(context.Background(), d.Id())
+	return diag.FromErr(err)
 }
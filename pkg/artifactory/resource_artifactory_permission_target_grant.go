@@ -0,0 +1,279 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v2 "github.com/rickardl/go-artifactory/v2/artifactory/v2"
+)
+
+// resourceArtifactoryPermissionTargetGrant manages the permissions of a single user or group in one section of a
+// permission target, leaving the rest of the target alone
+func resourceArtifactoryPermissionTargetGrant() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePermissionTargetGrantCreate,
+		ReadContext:   resourcePermissionTargetGrantRead,
+		UpdateContext: resourcePermissionTargetGrantUpdate,
+		DeleteContext: resourcePermissionTargetGrantDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"target": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"section": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(permissionGrantSections, false),
+			},
+			"user": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+			"group": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"user", "group"},
+			},
+			"permissions": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(permissionsV2, false),
+				},
+				Set: schema.HashString,
+			},
+		},
+	}
+}
+
+// permissionGrantSections are the sections of a permission target a grant can be made in
+var permissionGrantSections = []string{"repo", "build", "release_bundle"}
+
+// permissionGrantId splits an id around section and principal type, both target and name may contain ":". The
+// greedy target makes the last match win
+var permissionGrantId = regexp.MustCompile(`^(.+):(` + strings.Join(permissionGrantSections, "|") + `):(user|group):(.+)$`)
+
+// permissionGrant addresses a principal in a permission target section, its id is target:section:user|group:name
+type permissionGrant struct {
+	target        string
+	section       string
+	principalType string
+	name          string
+}
+
+func (g permissionGrant) id() string {
+	return strings.Join([]string{g.target, g.section, g.principalType, g.name}, ":")
+}
+
+func parsePermissionGrantId(id string) (permissionGrant, error) {
+	parts := permissionGrantId.FindStringSubmatch(id)
+	if parts == nil {
+		return permissionGrant{}, fmt.Errorf("invalid permission target grant id %s, expected target:section:user|group:name", id)
+	}
+	return permissionGrant{target: parts[1], section: parts[2], principalType: parts[3], name: parts[4]}, nil
+}
+
+func unpackPermissionGrant(d *schema.ResourceData) permissionGrant {
+	g := permissionGrant{
+		target:        d.Get("target").(string),
+		section:       d.Get("section").(string),
+		principalType: "user",
+		name:          d.Get("user").(string),
+	}
+	if group, ok := d.GetOk("group"); ok {
+		g.principalType = "group"
+		g.name = group.(string)
+	}
+	return g
+}
+
+func (g permissionGrant) permission(permissionTarget *PermissionTarget) *v2.Permission {
+	switch g.section {
+	case "repo":
+		return permissionTarget.Repo
+	case "build":
+		return permissionTarget.Build
+	case "release_bundle":
+		return permissionTarget.ReleaseBundle
+	}
+	return nil
+}
+
+func (g permissionGrant) principals(actions *v2.Entity) **map[string][]string {
+	if g.principalType == "group" {
+		return &actions.Groups
+	}
+	return &actions.Users
+}
+
+// get returns the permissions of the principal, false if it has none in the section
+func (g permissionGrant) get(permissionTarget *PermissionTarget) ([]string, bool) {
+	permission := g.permission(permissionTarget)
+	if permission == nil || permission.Actions == nil {
+		return nil, false
+	}
+
+	principals := *g.principals(permission.Actions)
+	if principals == nil {
+		return nil, false
+	}
+	permissions, ok := (*principals)[g.name]
+	return permissions, ok
+}
+
+// put sets the permissions of the principal, nil removes it from the section
+func (g permissionGrant) put(permissionTarget *PermissionTarget, permissions []string) error {
+	permission := g.permission(permissionTarget)
+	if permission == nil {
+		if permissions == nil {
+			return nil
+		}
+		return fmt.Errorf("permission target %s has no %s section", g.target, g.section)
+	}
+
+	if permission.Actions == nil {
+		permission.Actions = new(v2.Entity)
+	}
+	principals := g.principals(permission.Actions)
+	if *principals == nil {
+		*principals = &map[string][]string{}
+	}
+
+	if permissions == nil {
+		delete(**principals, g.name)
+	} else {
+		(**principals)[g.name] = permissions
+	}
+	return nil
+}
+
+func samePermissions(a, b []string) bool {
+	return schema.NewSet(schema.HashString, castToInterfaceArr(a)).Equal(schema.NewSet(schema.HashString, castToInterfaceArr(b)))
+}
+
+// applyPermissionGrant does a read-modify-write of the permission target. The lock keeps grants of this provider
+// from overwriting each other, but artifactory has no optimistic locking so writers elsewhere can still slip in
+// between the get and the put. The grant is read back and applied again if it got lost
+func applyPermissionGrant(ctx context.Context, c *ArtClient, g permissionGrant, permissions []string, timeout time.Duration) error {
+	permissionTargetLocks.Lock(g.target)
+	defer permissionTargetLocks.Unlock(g.target)
+
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		permissionTarget, resp, err := getPermissionTarget(ctx, c, g.target)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			if permissions == nil {
+				return nil
+			}
+			return resource.NonRetryableError(fmt.Errorf("permission target %s does not exist", g.target))
+		} else if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		if current, ok := g.get(permissionTarget); ok == (permissions != nil) && samePermissions(current, permissions) {
+			return nil
+		}
+		if err := g.put(permissionTarget, permissions); err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		resp, err = sendPermissionTarget(ctx, c, http.MethodPut, permissionTarget)
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			return resource.RetryableError(err)
+		} else if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		permissionTarget, _, err = getPermissionTarget(ctx, c, g.target)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if current, ok := g.get(permissionTarget); ok != (permissions != nil) || !samePermissions(current, permissions) {
+			return resource.RetryableError(fmt.Errorf("permission target %s was changed concurrently", g.target))
+		}
+		return nil
+	})
+}
+
+func resourcePermissionTargetGrantCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	g := unpackPermissionGrant(d)
+	permissions := castToStringArr(d.Get("permissions").(*schema.Set).List())
+	if err := applyPermissionGrant(ctx, c, g, permissions, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(g.id())
+	return resourcePermissionTargetGrantRead(ctx, d, m)
+}
+
+func resourcePermissionTargetGrantRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	g, err := parsePermissionGrantId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	permissionTarget, resp, err := getPermissionTarget(ctx, c, g.target)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	permissions, ok := g.get(permissionTarget)
+	if !ok {
+		d.SetId("")
+		return nil
+	}
+
+	p := newPacker("artifactory_permission_target_grant", d)
+	p.set("target", g.target)
+	p.set("section", g.section)
+	p.set(g.principalType, g.name)
+	p.set("permissions", schema.NewSet(schema.HashString, castToInterfaceArr(permissions)))
+	return diag.FromErr(p.err())
+}
+
+func resourcePermissionTargetGrantUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	permissions := castToStringArr(d.Get("permissions").(*schema.Set).List())
+	if err := applyPermissionGrant(ctx, c, unpackPermissionGrant(d), permissions, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePermissionTargetGrantRead(ctx, d, m)
+}
+
+func resourcePermissionTargetGrantDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	g, err := parsePermissionGrantId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(applyPermissionGrant(ctx, c, g, nil, d.Timeout(schema.TimeoutDelete)))
+}
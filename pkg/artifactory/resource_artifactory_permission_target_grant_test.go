@@ -0,0 +1,104 @@
+package artifactory
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	v2 "github.com/rickardl/go-artifactory/v2/artifactory/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const permissionGrantConfig = `
+resource "artifactory_permission_target" "test-perm" {
+  name = "test-perm-grant"
+
+  repo {
+    repositories = ["example-repo-local"]
+
+    actions {
+      users {
+        name        = "anonymous"
+        permissions = ["read"]
+      }
+    }
+  }
+
+  lifecycle {
+    ignore_changes = [repo]
+  }
+}
+
+resource "artifactory_permission_target_grant" "readers" {
+  target      = artifactory_permission_target.test-perm.name
+  section     = "repo"
+  group       = "readers"
+  permissions = ["read", "annotate"]
+}`
+
+func TestAccPermissionTargetGrant(t *testing.T) {
+	const id = "artifactory_permission_target_grant.readers"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testPermissionTargetCheckDestroy("artifactory_permission_target.test-perm"),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: permissionGrantConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "id", "test-perm-grant:repo:group:readers"),
+					resource.TestCheckResourceAttr(id, "permissions.#", "2"),
+				),
+			},
+			{
+				ResourceName:      id,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParsePermissionGrantId(t *testing.T) {
+	g, err := parsePermissionGrantId("perm:release_bundle:user:ldap:jane")
+	assert.NoError(t, err)
+	assert.Equal(t, permissionGrant{target: "perm", section: "release_bundle", principalType: "user", name: "ldap:jane"}, g)
+	assert.Equal(t, "perm:release_bundle:user:ldap:jane", g.id())
+
+	g, err = parsePermissionGrantId("team:ci:repo:group:readers")
+	assert.NoError(t, err)
+	assert.Equal(t, permissionGrant{target: "team:ci", section: "repo", principalType: "group", name: "readers"}, g)
+	assert.Equal(t, "team:ci:repo:group:readers", g.id())
+
+	_, err = parsePermissionGrantId("perm:repo:team:readers")
+	assert.Error(t, err)
+	_, err = parsePermissionGrantId("perm:artifacts:group:readers")
+	assert.Error(t, err)
+}
+
+func TestPermissionGrant_put(t *testing.T) {
+	repositories := []string{"example-repo-local"}
+	permissionTarget := new(PermissionTarget)
+	permissionTarget.Repo = &v2.Permission{
+		Repositories: &repositories,
+		Actions: &v2.Entity{
+			Users: &map[string][]string{"anonymous": {"read"}},
+		},
+	}
+
+	g := permissionGrant{target: "perm", section: "repo", principalType: "group", name: "readers"}
+	assert.NoError(t, g.put(permissionTarget, []string{"read", "write"}))
+	permissions, ok := g.get(permissionTarget)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"read", "write"}, permissions)
+	assert.Equal(t, map[string][]string{"anonymous": {"read"}}, *permissionTarget.Repo.Actions.Users)
+
+	assert.NoError(t, g.put(permissionTarget, nil))
+	_, ok = g.get(permissionTarget)
+	assert.False(t, ok)
+	assert.Equal(t, map[string][]string{"anonymous": {"read"}}, *permissionTarget.Repo.Actions.Users)
+
+	build := permissionGrant{target: "perm", section: "build", principalType: "user", name: "anonymous"}
+	assert.EqualError(t, build.put(permissionTarget, []string{"read"}), "permission target perm has no build section")
+	assert.NoError(t, build.put(permissionTarget, nil))
+}
@@ -2,12 +2,15 @@ package artifactory
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
 
-	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 const permissionNoIncludes = `
@@ -169,7 +172,7 @@ func TestAccPermissionTarget_addBuild(t *testing.T) {
 
 func testPermissionTargetCheckDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 
 		if !ok {
@@ -186,3 +189,134 @@ func testPermissionTargetCheckDestroy(id string) func(*terraform.State) error {
 		}
 	}
 }
+
+const permissionReleaseBundle = `
+resource "artifactory_permission_target" "test-perm" {
+  name = "test-perm"
+
+  release_bundle {
+    includes_pattern = ["**"]
+    repositories     = ["%s"]
+
+    actions {
+      users {
+        name        = "anonymous"
+        permissions = ["read"]
+      }
+    }
+  }
+}
+`
+
+func TestAccPermissionTarget_releaseBundle(t *testing.T) {
+	const id = "artifactory_permission_target.test-perm"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testPermissionTargetCheckDestroy(id),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(permissionReleaseBundle, "terraform-missing-release-bundles"),
+				ExpectError: regexp.MustCompile("release bundle repositories terraform-missing-release-bundles do not exist"),
+			},
+			{
+				Config: fmt.Sprintf(permissionReleaseBundle, "release-bundles"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "release_bundle.0.repositories.#", "1"),
+					resource.TestCheckResourceAttr(id, "release_bundle.0.includes_pattern.#", "1"),
+					resource.TestCheckResourceAttr(id, "release_bundle.0.actions.0.users.#", "1"),
+					resource.TestCheckResourceAttr(id, "repo.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestPermissionTarget_releaseBundle(t *testing.T) {
+	r := resourceArtifactoryPermissionTarget()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "test-perm",
+		"release_bundle": []interface{}{map[string]interface{}{
+			"repositories": []interface{}{"release-bundles"},
+			"actions": []interface{}{map[string]interface{}{
+				"groups": []interface{}{map[string]interface{}{
+					"name":        "readers",
+					"permissions": []interface{}{"read"},
+				}},
+			}},
+		}},
+	})
+
+	permissionTarget := unpackPermissionTarget(d)
+	body, err := json.Marshal(permissionTarget)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "test-perm",
+		"releaseBundle": {
+			"include-patterns": [],
+			"exclude-patterns": [],
+			"repositories": ["release-bundles"],
+			"actions": {"groups": {"readers": ["read"]}}
+		}
+	}`, string(body))
+
+	packed := r.Data(nil)
+	assert.NoError(t, packPermissionTarget(permissionTarget, packed))
+	assert.Equal(t, []interface{}{"release-bundles"}, packed.Get("release_bundle.0.repositories").(*schema.Set).List())
+	assert.Equal(t, 1, packed.Get("release_bundle.0.actions.0.groups").(*schema.Set).Len())
+	assert.Equal(t, 0, len(packed.Get("repo").([]interface{})))
+}
+
+const permissionValidated = `
+resource "artifactory_permission_target" "test-perm" {
+  name                = "test-perm"
+  validate_principals = true
+
+  repo {
+    repositories = [%q]
+
+    actions {
+      users {
+        name        = %q
+        permissions = ["read"]
+      }
+    }
+  }
+}
+`
+
+func TestAccPermissionTarget_validation(t *testing.T) {
+	const id = "artifactory_permission_target.test-perm"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testPermissionTargetCheckDestroy(id),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(permissionValidated, "ANY LOCALS", "anonymous"),
+				ExpectError: regexp.MustCompile(`unknown keyword "ANY LOCALS"`),
+			},
+			{
+				Config:      fmt.Sprintf(permissionValidated, "example-repo-local", "terraform-missing-user"),
+				ExpectError: regexp.MustCompile("user terraform-missing-user does not exist"),
+			},
+			{
+				Config: fmt.Sprintf(permissionValidated, "ANY LOCAL", "anonymous"),
+				Check:  resource.TestCheckResourceAttr(id, "repo.0.repositories.#", "1"),
+			},
+		},
+	})
+}
+
+func TestCheckSectionRepositories(t *testing.T) {
+	assert.Empty(t, checkSectionRepositories("repo", []string{"ANY", "ANY REMOTE", "anything-local"}))
+	assert.Empty(t, checkSectionRepositories("build", []string{"artifactory-build-info"}))
+	assert.Equal(t, []string{
+		`repo repositories: unknown keyword "any local", expected one of ANY, ANY LOCAL, ANY REMOTE, ANY DISTRIBUTION`,
+		`repo repositories: unknown keyword "ANY VIRTUAL", expected one of ANY, ANY LOCAL, ANY REMOTE, ANY DISTRIBUTION`,
+	}, checkSectionRepositories("repo", []string{"any local", "ANY VIRTUAL"}))
+	assert.Equal(t, []string{"build repositories can only be artifactory-build-info, got ANY"},
+		checkSectionRepositories("build", []string{"ANY"}))
+}
@@ -0,0 +1,187 @@
+package artifactory
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+// Permissions of the v1 api, m=admin; d=delete; w=deploy; n=annotate; r=read
+var permissionsV1 = []string{"m", "d", "w", "n", "r"}
+
+// resourceArtifactoryPermissionTargetV1 manages permission targets through /api/security/permissions, for
+// artifactory versions before 6.6.0 that don't have the v2 api yet
+func resourceArtifactoryPermissionTargetV1() *schema.Resource {
+	principalSchema := &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Set:      hashPrincipal,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"permissions": {
+					Type:     schema.TypeSet,
+					Required: true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice(permissionsV1, false),
+					},
+					Set: schema.HashString,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		CreateContext: resourcePermissionTargetV1Create,
+		ReadContext:   resourcePermissionTargetV1Read,
+		UpdateContext: resourcePermissionTargetV1Update,
+		DeleteContext: resourcePermissionTargetV1Delete,
+
+		SchemaVersion: 0,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"includes_pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"excludes_pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"repositories": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"users":  principalSchema,
+			"groups": principalSchema,
+		},
+	}
+}
+
+func unpackPrincipalsV1(set *schema.Set) *map[string][]string {
+	principals := make(map[string][]string)
+	for _, v := range set.List() {
+		p := v.(map[string]interface{})
+		principals[p["name"].(string)] = castToStringArr(p["permissions"].(*schema.Set).List())
+	}
+	return &principals
+}
+
+func packPrincipalsV1(principals *map[string][]string) *schema.Set {
+	set := schema.NewSet(hashPrincipal, []interface{}{})
+	if principals == nil {
+		return set
+	}
+
+	for name, permissions := range *principals {
+		set.Add(map[string]interface{}{
+			"name":        name,
+			"permissions": schema.NewSet(schema.HashString, castToInterfaceArr(permissions)),
+		})
+	}
+	return set
+}
+
+func unpackPermissionTargetV1(s *schema.ResourceData) *v1.PermissionTargets {
+	d := &ResourceData{s}
+
+	permissionTarget := new(v1.PermissionTargets)
+	permissionTarget.Name = d.getStringRef("name", false)
+	permissionTarget.IncludesPattern = d.getStringRef("includes_pattern", false)
+	permissionTarget.ExcludesPattern = d.getStringRef("excludes_pattern", false)
+	permissionTarget.Repositories = d.getSetRef("repositories")
+	permissionTarget.Principals = &v1.Principals{
+		Users:  unpackPrincipalsV1(d.Get("users").(*schema.Set)),
+		Groups: unpackPrincipalsV1(d.Get("groups").(*schema.Set)),
+	}
+
+	return permissionTarget
+}
+
+func packPermissionTargetV1(permissionTarget *v1.PermissionTargets, d *schema.ResourceData) error {
+	p := newPacker("artifactory_permission_target_v1", d)
+
+	p.set("name", permissionTarget.Name)
+	p.set("includes_pattern", permissionTarget.IncludesPattern)
+	p.set("excludes_pattern", permissionTarget.ExcludesPattern)
+	if permissionTarget.Repositories != nil {
+		p.set("repositories", schema.NewSet(schema.HashString, castToInterfaceArr(*permissionTarget.Repositories)))
+	}
+
+	principals := permissionTarget.Principals
+	if principals == nil {
+		principals = new(v1.Principals)
+	}
+	p.set("users", packPrincipalsV1(principals.Users))
+	p.set("groups", packPrincipalsV1(principals.Groups))
+
+	return p.err()
+}
+
+func resourcePermissionTargetV1Create(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	permissionTarget := unpackPermissionTargetV1(d)
+	if _, err := c.V1.Security.CreateOrReplacePermissionTargets(ctx, *permissionTarget.Name, permissionTarget); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*permissionTarget.Name)
+	return resourcePermissionTargetV1Read(ctx, d, m)
+}
+
+func resourcePermissionTargetV1Read(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	permissionTarget, resp, err := c.V1.Security.GetPermissionTargets(ctx, d.Id())
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(packPermissionTargetV1(permissionTarget, d))
+}
+
+func resourcePermissionTargetV1Update(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	permissionTarget := unpackPermissionTargetV1(d)
+	if _, err := c.V1.Security.CreateOrReplacePermissionTargets(ctx, d.Id(), permissionTarget); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePermissionTargetV1Read(ctx, d, m)
+}
+
+func resourcePermissionTargetV1Delete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	_, resp, err := c.V1.Security.DeletePermissionTargets(ctx, d.Id())
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return diag.FromErr(err)
+}
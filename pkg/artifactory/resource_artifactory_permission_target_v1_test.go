@@ -0,0 +1,75 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const permissionV1Full = `
+resource "artifactory_permission_target_v1" "test-perm" {
+	name             = "test-perm-v1"
+	includes_pattern = "foo/**"
+	excludes_pattern = "bar/**"
+	repositories     = ["example-repo-local"]
+
+	users {
+		name        = "anonymous"
+		permissions = ["r", "w"]
+	}
+
+	groups {
+		name        = "readers"
+		permissions = ["r"]
+	}
+}`
+
+func TestAccPermissionTargetV1_full(t *testing.T) {
+	const id = "artifactory_permission_target_v1.test-perm"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testPermissionTargetV1CheckDestroy(id),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: permissionV1Full,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "name", "test-perm-v1"),
+					resource.TestCheckResourceAttr(id, "includes_pattern", "foo/**"),
+					resource.TestCheckResourceAttr(id, "excludes_pattern", "bar/**"),
+					resource.TestCheckResourceAttr(id, "repositories.#", "1"),
+					resource.TestCheckResourceAttr(id, "users.#", "1"),
+					resource.TestCheckResourceAttr(id, "groups.#", "1"),
+				),
+			},
+			{
+				ResourceName:      id,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testPermissionTargetV1CheckDestroy(id string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ArtClient)
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("err: Resource id[%s] not found", id)
+		}
+
+		_, resp, err := client.V1.Security.GetPermissionTargets(context.Background(), rs.Primary.ID)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error: Request failed: %s", err.Error())
+		}
+		return fmt.Errorf("error: Permission target %s still exists", rs.Primary.ID)
+	}
+}
@@ -0,0 +1,20 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryRemoteDockerRepository() *schema.Resource {
+	return remoteRepositoryResource("docker", map[string]*schema.Schema{
+		"enable_token_authentication": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}, func(d *ResourceData, repo *v1.RemoteRepository) {
+		repo.EnableTokenAuthentication = d.getBoolRef("enable_token_authentication", true)
+	}, func(repo *v1.RemoteRepository, d *schema.ResourceData, p *packer) {
+		p.set("enable_token_authentication", repo.EnableTokenAuthentication)
+	})
+}
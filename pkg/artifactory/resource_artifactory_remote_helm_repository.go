@@ -0,0 +1,9 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceArtifactoryRemoteHelmRepository() *schema.Resource {
+	return remoteRepositoryResource("helm", nil, nil, nil)
+}
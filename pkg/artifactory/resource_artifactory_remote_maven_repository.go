@@ -0,0 +1,64 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryRemoteMavenRepository() *schema.Resource {
+	return remoteRepositoryResource("maven", map[string]*schema.Schema{
+		"handle_releases": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"handle_snapshots": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"max_unique_snapshots": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"suppress_pom_consistency_checks": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"fetch_jars_eagerly": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"fetch_sources_eagerly": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"reject_invalid_jars": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}, func(d *ResourceData, repo *v1.RemoteRepository) {
+		repo.HandleReleases = d.getBoolRef("handle_releases", true)
+		repo.HandleSnapshots = d.getBoolRef("handle_snapshots", true)
+		repo.MaxUniqueSnapshots = d.getIntRef("max_unique_snapshots", true)
+		repo.SuppressPomConsistencyChecks = d.getBoolRef("suppress_pom_consistency_checks", true)
+		repo.FetchJarsEagerly = d.getBoolRef("fetch_jars_eagerly", true)
+		repo.FetchSourcesEagerly = d.getBoolRef("fetch_sources_eagerly", true)
+		repo.RejectInvalidJars = d.getBoolRef("reject_invalid_jars", true)
+	}, func(repo *v1.RemoteRepository, d *schema.ResourceData, p *packer) {
+		p.set("handle_releases", repo.HandleReleases)
+		p.set("handle_snapshots", repo.HandleSnapshots)
+		p.set("max_unique_snapshots", repo.MaxUniqueSnapshots)
+		p.set("suppress_pom_consistency_checks", repo.SuppressPomConsistencyChecks)
+		p.set("fetch_jars_eagerly", repo.FetchJarsEagerly)
+		p.set("fetch_sources_eagerly", repo.FetchSourcesEagerly)
+		p.set("reject_invalid_jars", repo.RejectInvalidJars)
+	})
+}
@@ -0,0 +1,20 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryRemotePypiRepository() *schema.Resource {
+	return remoteRepositoryResource("pypi", map[string]*schema.Schema{
+		"pypi_registry_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "https://pypi.org",
+		},
+	}, func(d *ResourceData, repo *v1.RemoteRepository) {
+		repo.PyPiRegistryUrl = d.getStringRef("pypi_registry_url", true)
+	}, func(repo *v1.RemoteRepository, d *schema.ResourceData, p *packer) {
+		p.set("pypi_registry_url", repo.PyPiRegistryUrl)
+	})
+}
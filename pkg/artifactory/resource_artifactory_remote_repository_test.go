@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
+	"regexp"
+	"strings"
 	"testing"
 
-	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 const remoteRepoBasic = `
@@ -67,16 +70,16 @@ func TestAccRemoteRepository_nugetNew(t *testing.T) {
 const remoteRepoFull = `
 resource "artifactory_remote_repository" "terraform-remote-test-repo-full" {
     key                             	  = "terraform-remote-test-repo-full"
-	package_type                          = "npm"
-	url                                   = "https://registry.npmjs.org/"
+	package_type                          = "maven"
+	url                                   = "https://repo1.maven.org/maven2/"
 	username                              = "user"
 	password                              = "pass"
     proxy                                 = ""
 	description                           = "desc"
 	notes                                 = "notes"
-	includes_pattern                      = "**/*.js"
-	excludes_pattern                      = "**/*.jsx"
-	repo_layout_ref                       = "npm-default"
+	includes_pattern                      = "**/*.jar"
+	excludes_pattern                      = "**/*.war"
+	repo_layout_ref                       = "maven-2-default"
 	handle_releases                       = true
 	handle_snapshots                      = true
 	max_unique_snapshots                  = 15
@@ -112,16 +115,16 @@ func TestAccRemoteRepository_full(t *testing.T) {
 				Config: remoteRepoFull,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "key", "terraform-remote-test-repo-full"),
-					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "package_type", "npm"),
-					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "url", "https://registry.npmjs.org/"),
+					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "package_type", "maven"),
+					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "url", "https://repo1.maven.org/maven2/"),
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "username", "user"),
-					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "password", getMD5Hash("pass")),
+					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "password", secretDigest("terraform-remote-test-repo-full", 0, "pass")),
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "proxy", ""),
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "description", "desc (local file cache)"),
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "notes", "notes"),
-					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "includes_pattern", "**/*.js"),
-					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "excludes_pattern", "**/*.jsx"),
-					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "repo_layout_ref", "npm-default"),
+					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "includes_pattern", "**/*.jar"),
+					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "excludes_pattern", "**/*.war"),
+					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "repo_layout_ref", "maven-2-default"),
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "handle_releases", "true"),
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "handle_snapshots", "true"),
 					resource.TestCheckResourceAttr("artifactory_remote_repository.terraform-remote-test-repo-full", "max_unique_snapshots", "15"),
@@ -152,9 +155,114 @@ func TestAccRemoteRepository_full(t *testing.T) {
 	})
 }
 
+const remoteRepoSmartRemoteTemplate = `
+resource "artifactory_remote_repository" "terraform-remote-test-repo-smart" {
+	key          = "terraform-remote-test-repo-smart"
+	package_type = "generic"
+	url          = "%s/generic-local"
+
+	content_synchronisation {
+		enabled                         = true
+		statistics_enabled              = true
+		properties_enabled              = true
+		source_origin_absence_detection = true
+	}
+}`
+
+func TestAccRemoteRepository_contentSynchronisation(t *testing.T) {
+	const id = "artifactory_remote_repository.terraform-remote-test-repo-smart"
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: resourceRemoteRepositoryCheckDestroy(id),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(remoteRepoSmartRemoteTemplate, strings.TrimSuffix(os.Getenv("ARTIFACTORY_URL"), "/")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "content_synchronisation.#", "1"),
+					resource.TestCheckResourceAttr(id, "content_synchronisation.0.enabled", "true"),
+					resource.TestCheckResourceAttr(id, "content_synchronisation.0.statistics_enabled", "true"),
+					resource.TestCheckResourceAttr(id, "content_synchronisation.0.properties_enabled", "true"),
+					resource.TestCheckResourceAttr(id, "content_synchronisation.0.source_origin_absence_detection", "true"),
+				),
+			},
+			{
+				ResourceName:            id,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_destroy", "verify_connection", "verify_connection_path", "verify_connection_rollback"},
+			},
+		},
+	})
+}
+
+const remoteRepoNotSmartRemote = `
+resource "artifactory_remote_repository" "terraform-remote-test-repo-not-smart" {
+	key          = "terraform-remote-test-repo-not-smart"
+	package_type = "npm"
+	url          = "https://registry.npmjs.org/"
+
+	content_synchronisation {
+		enabled = true
+	}
+}`
+
+func TestAccRemoteRepository_contentSynchronisationNeedsArtifactory(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      remoteRepoNotSmartRemote,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("content_synchronisation can only be enabled when url points at another Artifactory instance"),
+			},
+		},
+	})
+}
+
+const remoteRepoUnreachable = `
+resource "artifactory_remote_repository" "terraform-remote-test-repo-unreachable" {
+	key                        = "terraform-remote-test-repo-unreachable"
+	package_type               = "generic"
+	url                        = "https://does-not-exist.invalid/"
+	verify_connection          = true
+	verify_connection_path     = "index.html"
+	verify_connection_rollback = true
+}`
+
+func TestAccRemoteRepository_verifyConnection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		CheckDestroy: func(*terraform.State) error {
+			// the failed repository must have been rolled back
+			c := testAccProvider.Meta().(*ArtClient)
+			if _, resp, err := c.V1.Repositories.GetRemote(context.Background(), "terraform-remote-test-repo-unreachable"); err == nil {
+				return fmt.Errorf("error: unreachable remote repository was not rolled back")
+			} else if resp == nil || (resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusBadRequest) {
+				return err
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      strings.Replace(remoteRepoUnreachable, `verify_connection_path     = "index.html"`, "", 1),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("all of `verify_connection,verify_connection_path` must be specified"),
+			},
+			{
+				Config:      remoteRepoUnreachable,
+				ExpectError: regexp.MustCompile("connection check of remote repository terraform-remote-test-repo-unreachable failed"),
+			},
+		},
+	})
+}
+
 func resourceRemoteRepositoryCheckDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 
 		if !ok {
@@ -172,3 +280,69 @@ func resourceRemoteRepositoryCheckDestroy(id string) func(*terraform.State) erro
 		}
 	}
 }
+
+func TestRemoteRepository_verifyConnectionPath(t *testing.T) {
+	r := resourceArtifactoryRemoteRepository()
+	config := map[string]interface{}{
+		"key":          "terraform-remote-test-repo",
+		"package_type": "npm",
+		"url":          "https://registry.npmjs.org/",
+	}
+
+	assert.False(t, r.Validate(terraform.NewResourceConfigRaw(config)).HasError())
+
+	config["verify_connection"] = true
+	assert.True(t, r.Validate(terraform.NewResourceConfigRaw(config)).HasError(), "verify_connection without a path")
+
+	config["verify_connection_path"] = "index.html"
+	assert.False(t, r.Validate(terraform.NewResourceConfigRaw(config)).HasError())
+
+	config["verify_connection_path"] = "/"
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+	assert.Contains(t, fmt.Sprint(err), "verify_connection_path must be set to a file of the upstream")
+}
+
+func TestRemoteRepository_stateUpgradeV0(t *testing.T) {
+	testStateUpgrade(t, resourceArtifactoryRemoteRepository(), 0, map[string]interface{}{
+		"id":           "terraform-remote-test-repo",
+		"key":          "terraform-remote-test-repo",
+		"package_type": "maven",
+		"url":          "https://repo1.maven.org/maven2/",
+		"username":     "user",
+		"password":     "legacy-salted-hash",
+	}, map[string]interface{}{
+		"id":                         "terraform-remote-test-repo",
+		"key":                        "terraform-remote-test-repo",
+		"package_type":               "maven",
+		"url":                        "https://repo1.maven.org/maven2/",
+		"username":                   "user",
+		"password":                   "",
+		"password_version":           0,
+		"force_destroy":              false,
+		"verify_connection":          false,
+		"verify_connection_path":     "",
+		"verify_connection_rollback": false,
+	})
+}
+
+// the deprecated nuget block stays in the schema, configs still using it must not change after upgrading
+func TestRemoteRepository_stateUpgradeNugetPlan(t *testing.T) {
+	nuget := map[string]interface{}{
+		"feed_context_path":     "api/v2",
+		"download_context_path": "api/v2/package",
+		"v3_feed_url":           "https://api.nuget.org/v3/index.json",
+	}
+
+	testUpgradedPlan(t, resourceArtifactoryRemoteRepository(), 0, map[string]interface{}{
+		"id":           "terraform-remote-test-repo",
+		"key":          "terraform-remote-test-repo",
+		"package_type": "nuget",
+		"url":          "https://www.nuget.org/",
+		"nuget":        []interface{}{nuget},
+	}, map[string]interface{}{
+		"key":          "terraform-remote-test-repo",
+		"package_type": "nuget",
+		"url":          "https://www.nuget.org/",
+		"nuget":        []interface{}{nuget},
+	}, "nuget", "feed_context_path", "download_context_path", "v3_feed_url")
+}
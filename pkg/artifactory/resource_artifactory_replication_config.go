@@ -3,93 +3,93 @@ package artifactory
 import (
 	"context"
 	"fmt"
-	"net/http"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rickardl/go-artifactory/v2/artifactory"
 	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
-	"github.com/hashicorp/terraform/helper/schema"
 )
 
 func resourceArtifactoryReplicationConfig() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceReplicationConfigCreate,
-		Read:   resourceReplicationConfigRead,
-		Update: resourceReplicationConfigUpdate,
-		Delete: resourceReplicationConfigDelete,
-		Exists: resourceReplicationConfigExists,
-
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+	resourceSchema := map[string]*schema.Schema{
+		"repo_key": {
+			Type:     schema.TypeString,
+			Required: true,
 		},
-
-		Schema: map[string]*schema.Schema{
-			"repo_key": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"cron_exp": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"enable_event_replication": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"replications": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"url": {
-							Type:     schema.TypeString,
-							Required: true,
-							ForceNew: true,
-						},
-						"socket_timeout_millis": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
-						},
-						"username": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"password": {
-							Type:      schema.TypeString,
-							Optional:  true,
-							Sensitive: true,
-							StateFunc: getMD5Hash,
-						},
-						"enabled": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Computed: true,
-						},
-						"sync_deletes": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Computed: true,
-						},
-						"sync_properties": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Computed: true,
-						},
-						"sync_statistics": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Computed: true,
-						},
-						"path_prefix": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
+		"cron_exp": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"enable_event_replication": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"replications": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"url": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+					"socket_timeout_millis": {
+						Type:     schema.TypeInt,
+						Optional: true,
+						Computed: true,
+					},
+					"username": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"password":         secretSchema(),
+					"password_version": secretVersionSchema(),
+					"enabled": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Computed: true,
+					},
+					"sync_deletes": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Computed: true,
+					},
+					"sync_properties": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Computed: true,
+					},
+					"sync_statistics": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Computed: true,
+					},
+					"path_prefix": {
+						Type:     schema.TypeString,
+						Optional: true,
 					},
 				},
 			},
 		},
 	}
+
+	return &schema.Resource{
+		CreateContext: resourceReplicationConfigCreate,
+		ReadContext:   resourceReplicationConfigRead,
+		UpdateContext: resourceReplicationConfigUpdate,
+		DeleteContext: resourceReplicationConfigDelete,
+
+		SchemaVersion:  1,
+		StateUpgraders: secretStateUpgraders(replicationConfigSchemaV0()),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
 }
 
 func unpackReplicationConfig(s *schema.ResourceData) *v1.ReplicationConfig {
@@ -149,9 +149,8 @@ func unpackReplicationConfig(s *schema.ResourceData) *v1.ReplicationConfig {
 				replication.PathPrefix = artifactory.String(prefix.(string))
 			}
 
-			if pass, ok := m["password"]; ok {
-				replication.Password = artifactory.String(pass.(string))
-			}
+			// the replications are replaced as a whole, a password that isn't sent is cleared
+			replication.Password = d.getConfiguredSecretRef("replications", i, "password")
 
 			*replicationConfig.Replications = append(*replicationConfig.Replications, replication)
 		}
@@ -161,12 +160,11 @@ func unpackReplicationConfig(s *schema.ResourceData) *v1.ReplicationConfig {
 }
 
 func packReplicationConfig(replicationConfig *v1.ReplicationConfig, d *schema.ResourceData) error {
-	hasErr := false
-	logErr := cascadingErr(&hasErr)
+	p := newPacker("artifactory_replication_config", d)
 
-	logErr(d.Set("repo_key", replicationConfig.RepoKey))
-	logErr(d.Set("cron_exp", replicationConfig.CronExp))
-	logErr(d.Set("enable_event_replication", replicationConfig.EnableEventReplication))
+	p.set("repo_key", replicationConfig.RepoKey)
+	p.set("cron_exp", replicationConfig.CronExp)
+	p.set("enable_event_replication", replicationConfig.EnableEventReplication)
 
 	if replicationConfig.Replications != nil {
 		var replications []map[string]interface{}
@@ -185,8 +183,11 @@ func packReplicationConfig(replicationConfig *v1.ReplicationConfig, d *schema.Re
 				replication["username"] = *repo.Username
 			}
 
-			if repo.Password != nil {
-				replication["password"] = getMD5Hash(*repo.Password)
+			// passwords come back encrypted, keep the digests of the configured ones
+			if i, ok := replicationIndex(d, repo.URL); ok {
+				key := fmt.Sprintf("replications.%d.password", i)
+				replication["password"] = stateSecretDigest(d, key)
+				replication["password_version"] = d.Get(key + "_version")
 			}
 
 			if repo.Enabled != nil {
@@ -212,74 +213,69 @@ func packReplicationConfig(replicationConfig *v1.ReplicationConfig, d *schema.Re
 			replications = append(replications, replication)
 		}
 
-		logErr(d.Set("replications", replications))
+		p.set("replications", replications)
 	}
 
-	if hasErr {
-		return fmt.Errorf("failed to pack replication config")
+	return p.err()
+}
+
+// replicationIndex finds the replication with the given url in the config, the server may list them in another order
+func replicationIndex(d *schema.ResourceData, url *string) (int, bool) {
+	if url == nil {
+		return 0, false
 	}
 
-	return nil
+	for i, raw := range d.Get("replications").([]interface{}) {
+		if replication, ok := raw.(map[string]interface{}); ok && replication["url"] == *url {
+			return i, true
+		}
+	}
+	return 0, false
 }
 
-func resourceReplicationConfigCreate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceReplicationConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	replicationConfig := unpackReplicationConfig(d)
 
-	_, err := c.V1.Artifacts.SetRepositoryReplicationConfig(context.Background(), *replicationConfig.RepoKey, replicationConfig)
+	_, err := c.V1.Artifacts.SetRepositoryReplicationConfig(ctx, *replicationConfig.RepoKey, replicationConfig)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*replicationConfig.RepoKey)
-	return resourceReplicationConfigRead(d, m)
+	return resourceReplicationConfigRead(ctx, d, m)
 }
 
-func resourceReplicationConfigRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceReplicationConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	replicationConfig, _, err := c.V1.Artifacts.GetRepositoryReplicationConfig(context.Background(), d.Id())
+	replicationConfig, _, err := c.V1.Artifacts.GetRepositoryReplicationConfig(ctx, d.Id())
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return packReplicationConfig(replicationConfig, d)
+	return diag.FromErr(packReplicationConfig(replicationConfig, d))
 }
 
-func resourceReplicationConfigUpdate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceReplicationConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	replicationConfig := unpackReplicationConfig(d)
-	_, err := c.V1.Artifacts.UpdateRepositoryReplicationConfig(context.Background(), d.Id(), replicationConfig)
+	_, err := c.V1.Artifacts.UpdateRepositoryReplicationConfig(ctx, d.Id(), replicationConfig)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*replicationConfig.RepoKey)
 
-	return resourceReplicationConfigRead(d, m)
+	return resourceReplicationConfigRead(ctx, d, m)
 }
 
-func resourceReplicationConfigDelete(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceReplicationConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 	replicationConfig := unpackReplicationConfig(d)
-	_, err := c.V1.Artifacts.DeleteRepositoryReplicationConfig(context.Background(), *replicationConfig.RepoKey)
-	return err
-}
-
-func resourceReplicationConfigExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	c := m.(*artifactory.Artifactory)
-
-	replicationName := d.Id()
-	_, resp, err := c.V1.Artifacts.GetRepositoryReplicationConfig(context.Background(), replicationName)
-
-	if resp.StatusCode == http.StatusNotFound {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	}
-
-	return true, nil
+	_, err := c.V1.Artifacts.DeleteRepositoryReplicationConfig(ctx, *replicationConfig.RepoKey)
+	return diag.FromErr(err)
 }
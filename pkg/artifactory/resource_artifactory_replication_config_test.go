@@ -7,9 +7,8 @@ import (
 	"os"
 	"testing"
 
-	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 const replicationConfigTemplate = `
@@ -59,7 +58,7 @@ func TestAccReplication_full(t *testing.T) {
 
 func testAccCheckReplicationDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 		if !ok {
 			return fmt.Errorf("err: Resource id[%s] not found", id)
@@ -76,3 +75,30 @@ func testAccCheckReplicationDestroy(id string) func(*terraform.State) error {
 		}
 	}
 }
+
+func TestReplicationConfig_stateUpgradeV0(t *testing.T) {
+	testStateUpgrade(t, resourceArtifactoryReplicationConfig(), 0, map[string]interface{}{
+		"id":       "lib-local",
+		"repo_key": "lib-local",
+		"cron_exp": "0 0 * * * ?",
+		"replications": []interface{}{
+			map[string]interface{}{
+				"url":      "https://example.com/artifactory/lib-local",
+				"username": "user",
+				"password": "legacy-salted-hash",
+			},
+		},
+	}, map[string]interface{}{
+		"id":       "lib-local",
+		"repo_key": "lib-local",
+		"cron_exp": "0 0 * * * ?",
+		"replications": []interface{}{
+			map[string]interface{}{
+				"url":              "https://example.com/artifactory/lib-local",
+				"username":         "user",
+				"password":         "",
+				"password_version": 0,
+			},
+		},
+	})
+}
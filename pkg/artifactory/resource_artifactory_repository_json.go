@@ -0,0 +1,264 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// writeOnlyRepositoryKeys are never returned as configured, e.g. remote passwords come back encrypted. The state
+// keeps their digests
+var writeOnlyRepositoryKeys = []string{"password"}
+
+func resourceArtifactoryRepositoryJson() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRepositoryJsonCreate,
+		ReadContext:   resourceRepositoryJsonRead,
+		UpdateContext: resourceRepositoryJsonUpdate,
+		DeleteContext: resourceRepositoryJsonDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"config": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				ValidateFunc:     validateRepositoryJson,
+				DiffSuppressFunc: suppressRepositoryJsonDiff,
+			},
+			"force_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+
+		CustomizeDiff: customdiff.All(
+			checkRepositoryJsonKey,
+			// artifactory can't change the class or package type of an existing repository
+			customdiff.ForceNewIfChange("config", func(_ context.Context, old, new, _ interface{}) bool {
+				o, err := parseRepositoryJson(old.(string))
+				if err != nil {
+					return false
+				}
+				n, err := parseRepositoryJson(new.(string))
+				if err != nil {
+					return false
+				}
+				return o["rclass"] != n["rclass"] || (n["packageType"] != nil && o["packageType"] != n["packageType"])
+			}),
+		),
+	}
+}
+
+func parseRepositoryJson(config string) (map[string]interface{}, error) {
+	var repo map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &repo); err != nil {
+		return nil, err
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("repository config must be a json object")
+	}
+	return repo, nil
+}
+
+func validateRepositoryJson(v interface{}, k string) ([]string, []error) {
+	repo, err := parseRepositoryJson(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%q is not a valid repository config: %s", k, err)}
+	}
+	if rclass, ok := repo["rclass"].(string); !ok || rclass == "" {
+		return nil, []error{fmt.Errorf("%q must set rclass", k)}
+	}
+	return nil, nil
+}
+
+func checkRepositoryJsonKey(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	repo, err := parseRepositoryJson(d.Get("config").(string))
+	if err != nil {
+		// unknown until apply
+		return nil
+	}
+	if key, ok := repo["key"]; ok && key != d.Get("key").(string) {
+		return fmt.Errorf("config sets key %v, but the resource manages %s", key, d.Get("key"))
+	}
+	return nil
+}
+
+// projectJson keeps the parts of actual that are present in shape. Objects are narrowed key by key,
+// anything else, lists included, is taken from actual as a whole
+func projectJson(shape, actual interface{}) interface{} {
+	s, ok := shape.(map[string]interface{})
+	a, isMap := actual.(map[string]interface{})
+	if !ok || !isMap {
+		return actual
+	}
+
+	projected := make(map[string]interface{}, len(s))
+	for k, v := range s {
+		if av, ok := a[k]; ok {
+			projected[k] = projectJson(v, av)
+		}
+	}
+	return projected
+}
+
+// digestWriteOnlyKeys replaces the write-only keys of a repository document by their digests
+func digestWriteOnlyKeys(id string, repo map[string]interface{}) {
+	for _, k := range writeOnlyRepositoryKeys {
+		if v, ok := repo[k].(string); ok {
+			repo[k] = secretDigest(id, 0, v)
+		}
+	}
+}
+
+// suppressRepositoryJsonDiff only compares the keys present in the configured document, so settings
+// defaulted by the server and formatting don't show up as changes. Write-only keys are compared by digest
+func suppressRepositoryJsonDiff(_, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return false
+	}
+	var o interface{}
+	if json.Unmarshal([]byte(old), &o) != nil {
+		return false
+	}
+	n, err := parseRepositoryJson(new)
+	if err != nil {
+		return false
+	}
+	digestWriteOnlyKeys(d.Id(), n)
+	return reflect.DeepEqual(projectJson(n, o), n)
+}
+
+func getRepositoryJson(ctx context.Context, c *ArtClient, key string) (map[string]interface{}, *http.Response, error) {
+	req, err := c.Raw.NewRequest(http.MethodGet, fmt.Sprintf("/api/repositories/%s", key), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var repo map[string]interface{}
+	resp, err := c.Raw.Do(ctx, req, &repo)
+	return repo, resp, err
+}
+
+func sendRepositoryJson(ctx context.Context, c *ArtClient, method string, d *schema.ResourceData) error {
+	repo, err := parseRepositoryJson(d.Get("config").(string))
+	if err != nil {
+		return err
+	}
+	repo["key"] = d.Get("key").(string)
+
+	req, err := c.Raw.NewJSONEncodedRequest(method, fmt.Sprintf("/api/repositories/%s", repo["key"]), repo)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Raw.Do(ctx, req, nil)
+	return err
+}
+
+func resourceRepositoryJsonCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	if err := sendRepositoryJson(ctx, c, http.MethodPut, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("key").(string))
+	return resourceRepositoryJsonRead(ctx, d, m)
+}
+
+func resourceRepositoryJsonRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	repo, resp, err := getRepositoryJson(ctx, c, d.Id())
+	if repositoryNotFound(resp) {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// on import there is no config yet and the state gets the full document
+	var config interface{} = repo
+	if shape, err := parseRepositoryJson(d.Get("config").(string)); err == nil {
+		projected := projectJson(shape, repo).(map[string]interface{})
+		for _, k := range writeOnlyRepositoryKeys {
+			if v, ok := shape[k]; ok {
+				projected[k] = v
+			}
+		}
+		// after a change the config holds write-only keys as configured, otherwise the state already has the digests
+		if d.HasChange("config") {
+			digestWriteOnlyKeys(d.Id(), projected)
+		}
+		config = projected
+	}
+
+	packed, err := json.Marshal(config)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	p := newPacker("artifactory_repository_json", d)
+	p.set("key", d.Id())
+	p.set("config", string(packed))
+
+	return diag.FromErr(p.err())
+}
+
+func resourceRepositoryJsonUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	// an unchanged config is the state, which only holds the digests of write-only keys
+	if d.HasChange("config") {
+		if err := sendRepositoryJson(ctx, c, http.MethodPost, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceRepositoryJsonRead(ctx, d, m)
+}
+
+func resourceRepositoryJsonDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
+
+	// Deleting a virtual repository never deletes artifacts, its listing is the content of its members
+	repo, _ := parseRepositoryJson(d.Get("config").(string))
+	if repo["rclass"] != "virtual" {
+		storageKey := d.Id()
+		if repo["rclass"] == "remote" {
+			storageKey += "-cache"
+		}
+		if diags := checkRepositoryDestroy(ctx, c, d, storageKey); diags.HasError() {
+			return diags
+		}
+	}
+
+	req, err := c.Raw.NewRequest(http.MethodDelete, fmt.Sprintf("/api/repositories/%s", d.Id()), nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := c.Raw.Do(ctx, req, nil)
+	if repositoryNotFound(resp) {
+		return nil
+	}
+	return diag.FromErr(err)
+}
@@ -0,0 +1,121 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressRepositoryJsonDiff(t *testing.T) {
+	const state = `{"key":"foo","rclass":"local","packageType":"maven","handleSnapshots":true,"maxUniqueSnapshots":0,"propertySets":["artifactory"]}`
+	d := resourceArtifactoryRepositoryJson().TestResourceData()
+	d.SetId("foo")
+
+	// formatting, key order and server defaults missing from the config are not a diff
+	assert.True(t, suppressRepositoryJsonDiff("config", state, `{
+		"packageType": "maven",
+		"rclass":      "local"
+	}`, d))
+	assert.True(t, suppressRepositoryJsonDiff("config", state, `{"rclass":"local","maxUniqueSnapshots":0.0}`, d))
+
+	assert.False(t, suppressRepositoryJsonDiff("config", state, `{"rclass":"local","handleSnapshots":false}`, d))
+	assert.False(t, suppressRepositoryJsonDiff("config", state, `{"rclass":"local","propertySets":[]}`, d))
+	assert.False(t, suppressRepositoryJsonDiff("config", state, `{"rclass":"local","description":"foo"}`, d))
+	assert.False(t, suppressRepositoryJsonDiff("config", "", `{"rclass":"local"}`, d))
+
+	// the state holds the digests of write-only keys
+	remote := fmt.Sprintf(`{"key":"foo","rclass":"remote","password":%q}`, secretDigest("foo", 0, "secret"))
+	assert.True(t, suppressRepositoryJsonDiff("config", remote, `{"rclass":"remote","password":"secret"}`, d))
+	assert.False(t, suppressRepositoryJsonDiff("config", remote, `{"rclass":"remote","password":"other"}`, d))
+}
+
+func TestProjectJson(t *testing.T) {
+	shape := map[string]interface{}{
+		"rclass":  "remote",
+		"url":     "https://example.com",
+		"missing": true,
+		"contentSynchronisation": map[string]interface{}{
+			"enabled": true,
+		},
+	}
+	actual := map[string]interface{}{
+		"rclass":       "remote",
+		"url":          "https://example.org",
+		"packageType":  "generic",
+		"propertySets": []interface{}{"artifactory"},
+		"contentSynchronisation": map[string]interface{}{
+			"enabled":    false,
+			"statistics": map[string]interface{}{"enabled": false},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"rclass": "remote",
+		"url":    "https://example.org",
+		"contentSynchronisation": map[string]interface{}{
+			"enabled": false,
+		},
+	}, projectJson(shape, actual))
+}
+
+func TestAccRepositoryJson_basic(t *testing.T) {
+	const id = "artifactory_repository_json.terraform-json-test-repo"
+	config := func(description string) string {
+		return fmt.Sprintf(`
+resource "artifactory_repository_json" "terraform-json-test-repo" {
+	key    = "terraform-json-test-repo"
+	config = jsonencode({
+		rclass      = "local"
+		packageType = "generic"
+		description = "%s"
+	})
+}`, description)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: resourceRepositoryJsonCheckDestroy(id),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config("created"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(id, "key", "terraform-json-test-repo"),
+					resource.TestCheckResourceAttr(id, "config", `{"description":"created","packageType":"generic","rclass":"local"}`),
+				),
+			},
+			{
+				Config: config("updated"),
+				Check:  resource.TestCheckResourceAttr(id, "config", `{"description":"updated","packageType":"generic","rclass":"local"}`),
+			},
+			{
+				ResourceName:            id,
+				ImportState:             true,
+				ImportStateVerifyIgnore: []string{"config", "force_destroy"},
+				ImportStateVerify:       true,
+			},
+		},
+	})
+}
+
+func resourceRepositoryJsonCheckDestroy(id string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ArtClient)
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("err: Resource id[%s] not found", id)
+		}
+
+		_, resp, err := getRepositoryJson(context.Background(), client, rs.Primary.ID)
+		if repositoryNotFound(resp) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error: Request failed: %s", err.Error())
+		}
+		return fmt.Errorf("error: Repository %s still exists", rs.Primary.ID)
+	}
+}
@@ -2,84 +2,82 @@ package artifactory
 
 import (
 	"context"
-	"fmt"
-	"github.com/rickardl/go-artifactory/v2/artifactory"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rickardl/go-artifactory/v2/artifactory/v1"
-	"github.com/hashicorp/terraform/helper/schema"
-	"net/http"
 )
 
 func resourceArtifactorySingleReplicationConfig() *schema.Resource {
+	resourceSchema := map[string]*schema.Schema{
+		"repo_key": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"cron_exp": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"enable_event_replication": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"url": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		"socket_timeout_millis": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+		"username": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"password":         secretSchema(),
+		"password_version": secretVersionSchema(),
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"sync_deletes": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"sync_properties": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"sync_statistics": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"path_prefix": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	}
+
 	return &schema.Resource{
-		Create: resourceSingleReplicationConfigCreate,
-		Read:   resourceSingleReplicationConfigRead,
-		Update: resourceSingleReplicationConfigUpdate,
-		Delete: resourceSingleReplicationConfigDelete,
-		Exists: resourceSingleReplicationConfigExists,
+		CreateContext: resourceSingleReplicationConfigCreate,
+		ReadContext:   resourceSingleReplicationConfigRead,
+		UpdateContext: resourceSingleReplicationConfigUpdate,
+		DeleteContext: resourceSingleReplicationConfigDelete,
+
+		SchemaVersion:  1,
+		StateUpgraders: secretStateUpgraders(singleReplicationConfigSchemaV0()),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
-		Schema: map[string]*schema.Schema{
-			"repo_key": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"cron_exp": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"enable_event_replication": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"url": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-			},
-			"socket_timeout_millis": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
-			},
-			"username": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"password": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Sensitive: true,
-				StateFunc: getMD5Hash,
-			},
-			"enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"sync_deletes": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"sync_properties": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"sync_statistics": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"path_prefix": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-		},
+		Schema: resourceSchema,
 	}
 }
 
@@ -98,126 +96,104 @@ func unpackSingleReplicationConfig(s *schema.ResourceData) *v1.SingleReplication
 	replicationConfig.SyncProperties = d.getBoolRef("sync_properties", false)
 	replicationConfig.SyncStatistics = d.getBoolRef("sync_statistics", false)
 	replicationConfig.PathPrefix = d.getStringRef("path_prefix", false)
-	replicationConfig.Password = d.getStringRef("password", false)
+	// the replication is replaced as a whole, a password that isn't sent is cleared
+	replicationConfig.Password = d.getConfiguredSecretRef("password")
 
 	return replicationConfig
 }
 
 func packSingleReplicationConfig(replicationConfig *v1.ReplicationConfig, d *schema.ResourceData) error {
-	hasErr := false
-	logErr := cascadingErr(&hasErr)
+	p := newPacker("artifactory_single_replication_config", d)
 
-	logErr(d.Set("repo_key", replicationConfig.RepoKey))
-	logErr(d.Set("cron_exp", replicationConfig.CronExp))
-	logErr(d.Set("enable_event_replication", replicationConfig.EnableEventReplication))
+	p.set("repo_key", replicationConfig.RepoKey)
+	p.set("cron_exp", replicationConfig.CronExp)
+	p.set("enable_event_replication", replicationConfig.EnableEventReplication)
 
 	firstConfig := (*replicationConfig.Replications)[0]
 
 	if firstConfig.URL != nil {
-		logErr(d.Set("url", *firstConfig.URL))
+		p.set("url", *firstConfig.URL)
 	}
 
 	if firstConfig.SocketTimeoutMillis != nil {
-		logErr(d.Set("socket_timeout_millis", *firstConfig.SocketTimeoutMillis))
+		p.set("socket_timeout_millis", *firstConfig.SocketTimeoutMillis)
 	}
 
 	if firstConfig.Username != nil {
-		logErr(d.Set("username", *firstConfig.Username))
+		p.set("username", *firstConfig.Username)
 	}
 
-	if firstConfig.Password != nil {
-		logErr(d.Set("password", getMD5Hash(*firstConfig.Password)))
-	}
+	// the password comes back encrypted, the state keeps the digest of the configured one
+	p.set("password", stateSecretDigest(d, "password"))
 
 	if firstConfig.Enabled != nil {
-		logErr(d.Set("enabled", *firstConfig.Enabled))
+		p.set("enabled", *firstConfig.Enabled)
 	}
 
 	if firstConfig.SyncDeletes != nil {
-		logErr(d.Set("sync_deletes", *firstConfig.SyncDeletes))
+		p.set("sync_deletes", *firstConfig.SyncDeletes)
 	}
 
 	if firstConfig.SyncProperties != nil {
-		logErr(d.Set("sync_properties", *firstConfig.SyncProperties))
+		p.set("sync_properties", *firstConfig.SyncProperties)
 	}
 
 	if firstConfig.SyncStatistics != nil {
-		logErr(d.Set("sync_statistics", *firstConfig.SyncStatistics))
+		p.set("sync_statistics", *firstConfig.SyncStatistics)
 	}
 
 	if firstConfig.PathPrefix != nil {
-		logErr(d.Set("path_prefix", *firstConfig.PathPrefix))
+		p.set("path_prefix", *firstConfig.PathPrefix)
 	}
 
-	if hasErr {
-		return fmt.Errorf("failed to pack replication config")
-	}
-
-	return nil
+	return p.err()
 }
 
-func resourceSingleReplicationConfigCreate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceSingleReplicationConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	replicationConfig := unpackSingleReplicationConfig(d)
 
-	_, err := c.V1.Artifacts.SetSingleRepositoryReplicationConfig(context.Background(), *replicationConfig.RepoKey, replicationConfig)
+	_, err := c.V1.Artifacts.SetSingleRepositoryReplicationConfig(ctx, *replicationConfig.RepoKey, replicationConfig)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*replicationConfig.RepoKey)
-	return resourceSingleReplicationConfigRead(d, m)
+	return resourceSingleReplicationConfigRead(ctx, d, m)
 }
 
-func resourceSingleReplicationConfigRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceSingleReplicationConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	replicationConfig, _, err := c.V1.Artifacts.GetRepositoryReplicationConfig(context.Background(), d.Id())
+	replicationConfig, _, err := c.V1.Artifacts.GetRepositoryReplicationConfig(ctx, d.Id())
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	} else if len(*replicationConfig.Replications) > 1 {
-		return fmt.Errorf("resource_single_replication_config does not support multiple replication config on a repo. Use resource_artifactory_replication_config instead")
+		return diag.Errorf("resource_single_replication_config does not support multiple replication config on a repo. Use resource_artifactory_replication_config instead")
 	}
 
-	return packSingleReplicationConfig(replicationConfig, d)
+	return diag.FromErr(packSingleReplicationConfig(replicationConfig, d))
 }
 
-func resourceSingleReplicationConfigUpdate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceSingleReplicationConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	replicationConfig := unpackSingleReplicationConfig(d)
-	_, err := c.V1.Artifacts.UpdateSingleRepositoryReplicationConfig(context.Background(), d.Id(), replicationConfig)
+	_, err := c.V1.Artifacts.UpdateSingleRepositoryReplicationConfig(ctx, d.Id(), replicationConfig)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*replicationConfig.RepoKey)
 
-	return resourceSingleReplicationConfigRead(d, m)
+	return resourceSingleReplicationConfigRead(ctx, d, m)
 }
 
-func resourceSingleReplicationConfigDelete(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceSingleReplicationConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 	replicationConfig := unpackSingleReplicationConfig(d)
-	_, err := c.V1.Artifacts.DeleteRepositoryReplicationConfig(context.Background(), *replicationConfig.RepoKey)
-	return err
-}
-
-func resourceSingleReplicationConfigExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	c := m.(*artifactory.Artifactory)
-
-	replicationName := d.Id()
-	replicationConfig, resp, err := c.V1.Artifacts.GetRepositoryReplicationConfig(context.Background(), replicationName)
-
-	if resp.StatusCode == http.StatusNotFound {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	} else if len(*replicationConfig.Replications) > 1 {
-		return false, fmt.Errorf("resource_single_replication_config does not support multiple replication config on a repo. Use resource_artifactory_replication_config instead")
-	}
-
-	return true, nil
+	_, err := c.V1.Artifacts.DeleteRepositoryReplicationConfig(ctx, *replicationConfig.RepoKey)
+	return diag.FromErr(err)
 }
@@ -1,4 +1,4 @@
-package artifactory_test
+package artifactory
 
 import (
 	"context"
@@ -7,9 +7,8 @@ import (
 	"os"
 	"testing"
 
-	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 const singleReplicationConfigTemplate = `
@@ -47,7 +46,7 @@ func TestAccSingleReplication_full(t *testing.T) {
 					resource.TestCheckResourceAttr("artifactory_single_replication_config.lib-local", "enable_event_replication", "true"),
 					resource.TestCheckResourceAttr("artifactory_single_replication_config.lib-local", "url", os.Getenv("ARTIFACTORY_URL")),
 					resource.TestCheckResourceAttr("artifactory_single_replication_config.lib-local", "username", os.Getenv("ARTIFACTORY_USERNAME")),
-					resource.TestCheckResourceAttr("artifactory_single_replication_config.lib-local", "password", os.Getenv("ARTIFACTORY_PASSWORD")),
+					resource.TestCheckResourceAttr("artifactory_single_replication_config.lib-local", "password", secretDigest("lib-local", 0, os.Getenv("ARTIFACTORY_PASSWORD"))),
 				),
 			},
 		},
@@ -56,7 +55,7 @@ func TestAccSingleReplication_full(t *testing.T) {
 
 func testAccCheckSingleReplicationDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 		if !ok {
 			return fmt.Errorf("err: Resource id[%s] not found", id)
@@ -2,86 +2,139 @@ package artifactory
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
+	"crypto/rand"
 	"fmt"
-	"math/rand"
+	"log"
+	"math/big"
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/rickardl/go-artifactory/v2/artifactory"
 	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
 )
 
-const randomPasswordLength = 16
-
 func resourceArtifactoryUser() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceUserCreate,
-		Read:   resourceUserRead,
-		Update: resourceUserUpdate,
-		Delete: resourceUserDelete,
-		Exists: resourceUserExists,
-
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+	resourceSchema := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
 		},
-
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-			},
-			"email": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"admin": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"profile_updatable": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"disable_ui_access": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"internal_password_disabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"groups": {
-				Type:     schema.TypeSet,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Set:      schema.HashString,
-				Optional: true,
-			},
-			"password": {
-				Type:      schema.TypeString,
-				Sensitive: true,
-				Optional:  true,
-				StateFunc: func(value interface{}) string {
-					// Avoid storing the actual value in the state and instead store the hash of it
-					return hashString(value.(string))
+		"email": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"admin": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"profile_updatable": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"disable_ui_access": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"internal_password_disabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"groups": {
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Set:      schema.HashString,
+			Optional: true,
+			Computed: true,
+		},
+		"password":         secretSchema(),
+		"password_version": secretVersionSchema(),
+		"password_generator": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"length": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Default:      16,
+						ValidateFunc: validation.IntBetween(8, 128),
+					},
+					"lower": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+					"upper": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+					"numeric": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+					"special": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
 				},
 			},
 		},
+		"generated_password": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"locked_out": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"realm": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateLowerCase,
+		},
+		"last_logged_in": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
 	}
-}
 
-// hashString do a sha256 checksum, encode it in base64 and return it as string
-// The choice of sha256 for checksum is arbitrary.
-func hashString(str string) string {
-	hash := sha256.Sum256([]byte(str))
-	return base64.StdEncoding.EncodeToString(hash[:])
+	return &schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+
+		CustomizeDiff: customdiff.All(
+			userGroupsCustomizeDiff,
+			userLockedOutCustomizeDiff,
+			userRealmCustomizeDiff,
+		),
+
+		SchemaVersion:  1,
+		StateUpgraders: secretStateUpgraders(userSchemaV0()),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resourceSchema,
+	}
 }
 
 func unpackUser(s *schema.ResourceData) *v1.User {
@@ -95,55 +148,85 @@ func unpackUser(s *schema.ResourceData) *v1.User {
 	user.DisableUIAccess = d.getBoolRef("disable_ui_access", false)
 	user.InternalPasswordDisabled = d.getBoolRef("internal_password_disabled", false)
 	user.Groups = d.getSetRef("groups")
-	user.Password = d.getStringRef("password", true)
+	user.Password = d.getSecretRef("password")
+	user.Realm = d.getStringRef("realm", false)
 
 	return user
 }
 
+// authenticatesElsewhere tells whether the user only logs in through an external realm. Their internal password would
+// let them in when the realm is unreachable, so it is disabled. In offline mode artifactory makes no outbound
+// connections and the realm may not be reachable at all, users keep their internal password there
+func authenticatesElsewhere(ctx context.Context, c *ArtClient, d *schema.ResourceData) (bool, error) {
+	if !externalRealm(d.Get("realm").(string)) {
+		return false, nil
+	}
+	offline, err := offlineMode(ctx, c)
+	return !offline, err
+}
+
 func packUser(user *v1.User, d *schema.ResourceData) error {
-	hasErr := false
-	logErr := cascadingErr(&hasErr)
+	p := newPacker("artifactory_user", d)
 
-	logErr(d.Set("name", user.Name))
-	logErr(d.Set("email", user.Email))
-	logErr(d.Set("admin", user.Admin))
-	logErr(d.Set("profile_updatable", user.ProfileUpdatable))
-	logErr(d.Set("disable_ui_access", user.DisableUIAccess))
-	logErr(d.Set("internal_password_disabled", user.InternalPasswordDisabled))
+	p.set("name", user.Name)
+	p.set("email", user.Email)
+	p.set("admin", user.Admin)
+	p.set("profile_updatable", user.ProfileUpdatable)
+	p.set("disable_ui_access", user.DisableUIAccess)
+	p.set("internal_password_disabled", user.InternalPasswordDisabled)
 
 	if user.Groups != nil {
-		logErr(d.Set("groups", schema.NewSet(schema.HashString, castToInterfaceArr(*user.Groups))))
+		p.set("groups", schema.NewSet(schema.HashString, castToInterfaceArr(*user.Groups)))
 	}
 
-	if hasErr {
-		return fmt.Errorf("failed to pack user")
-	}
+	p.set("realm", user.Realm)
+	p.set("last_logged_in", user.LastLoggedIn)
 
-	return nil
+	return p.err()
 }
 
-func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	user := unpackUser(d)
 
 	if user.Name == nil {
-		return fmt.Errorf("user name cannot be nil")
+		return diag.Errorf("user name cannot be nil")
 	}
 
+	external, err := authenticatesElsewhere(ctx, c, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if external {
+		user.InternalPasswordDisabled = artifactory.Bool(true)
+	}
+
+	var generated string
 	if user.Password == nil {
-		user.Password = artifactory.String(generatePassword())
+		var err error
+		if generated, err = unpackPasswordGenerator(d).generate(); err != nil {
+			return diag.FromErr(err)
+		}
+		user.Password = artifactory.String(generated)
+	}
+	// the api requires a password, users of external realms get one they can't use
+	if external {
+		generated = ""
 	}
 
-	_, err := c.V1.Security.CreateOrReplaceUser(context.Background(), *user.Name, user)
-	if err != nil {
-		return err
+	if _, err := c.V1.Security.CreateOrReplaceUser(ctx, *user.Name, user); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*user.Name)
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		c := m.(*artifactory.Artifactory)
-		_, resp, err := c.V1.Security.GetUser(context.Background(), d.Id())
+	// only known right now, the password can't be read back
+	if err := d.Set("generated_password", generated); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, resp, err := c.V1.Security.GetUser(ctx, d.Id())
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("error describing user: %s", err))
 		}
@@ -152,72 +235,210 @@ func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
 			return resource.RetryableError(fmt.Errorf("expected user to be created, but currently not found"))
 		}
 
-		return resource.NonRetryableError(resourceUserRead(d, m))
+		return nil
 	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceUserRead(ctx, d, m)
 }
 
-func resourceUserRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
-	user, resp, err := c.V1.Security.GetUser(context.Background(), d.Id())
-	if resp.StatusCode == http.StatusNotFound {
+	user, resp, err := c.V1.Security.GetUser(ctx, d.Id())
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	} else if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return packUser(user, d)
-}
-
-func resourceUserExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	c := m.(*artifactory.Artifactory)
+	if err := packUser(user, d); err != nil {
+		return diag.FromErr(err)
+	}
 
-	userId := d.Id()
-	_, resp, err := c.V1.Security.GetUser(context.Background(), userId)
-	if resp.StatusCode == http.StatusNotFound {
-		return false, nil
-	} else if err != nil {
-		return false, err
+	lockedOut, err := userLockedOut(ctx, c, d.Id())
+	if err != nil {
+		// the lock status is informational, it shouldn't keep users that aren't admins from reading users
+		log.Printf("[WARN] failed to read lock status of user %s: %s", d.Id(), err)
+	} else if err := d.Set("locked_out", lockedOut); err != nil {
+		return diag.FromErr(err)
 	}
 
-	return true, nil
+	return diag.FromErr(d.Set("password", stateSecretDigest(d, "password")))
 }
 
-func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 
 	user := unpackUser(d)
 	if user.Password != nil && len(*user.Password) == 0 {
 		user.Password = nil
 	}
+	// Groups may be managed by artifactory_group_members or artifactory_group instead
+	if !d.HasChange("groups") {
+		user.Groups = nil
+	}
 
-	_, err := c.V1.Security.UpdateUser(context.Background(), d.Id(), user)
+	external, err := authenticatesElsewhere(ctx, c, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	if external {
+		user.InternalPasswordDisabled = artifactory.Bool(true)
+	}
+
+	if _, err := c.V1.Security.UpdateUser(ctx, d.Id(), user); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("locked_out") && !d.Get("locked_out").(bool) {
+		if _, _, err := c.V1.Security.UnlockUser(ctx, d.Id()); err != nil {
+			return diag.Errorf("failed to unlock user %s: %s", d.Id(), err)
+		}
 	}
 
 	d.SetId(*user.Name)
-	return resourceUserRead(d, m)
+	return resourceUserRead(ctx, d, m)
 }
 
-func resourceUserDelete(d *schema.ResourceData, m interface{}) error {
-	c := m.(*artifactory.Artifactory)
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ArtClient)
 	user := unpackUser(d)
-	_, resp, err := c.V1.Security.DeleteUser(context.Background(), *user.Name)
-	if resp.StatusCode == http.StatusNotFound {
+	_, resp, err := c.V1.Security.DeleteUser(ctx, *user.Name)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return diag.FromErr(err)
+}
+
+// userLockedOut tells whether the user was locked out after too many failed logins. go-artifactory's
+// GetLockedOutUsers can't decode the answer, so it goes through the raw client
+func userLockedOut(ctx context.Context, c *ArtClient, name string) (bool, error) {
+	req, err := c.Raw.NewRequest(http.MethodGet, "/api/security/lockedUsers", nil)
+	if err != nil {
+		return false, err
+	}
+
+	var users []string
+	if _, err := c.Raw.Do(ctx, req, &users); err != nil {
+		return false, err
+	}
+	return containsString(users, name), nil
+}
+
+// userLockedOutCustomizeDiff only allows unlocking, users are locked by artifactory itself
+func userLockedOutCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if configured(d, "locked_out") && d.Get("locked_out").(bool) && d.HasChange("locked_out") {
+		return fmt.Errorf("locked_out can only be set to false to unlock the user")
+	}
+	return nil
+}
+
+// externalRealm tells whether users of realm are authenticated by ldap, saml, crowd and the like
+func externalRealm(realm string) bool {
+	return realm != "" && realm != "internal"
+}
+
+// userRealmCustomizeDiff rejects passwords for users of external realms, they wouldn't be used. Artifactory in
+// offline mode is only asked when there is something to reject
+func userRealmCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	realm := d.Get("realm").(string)
+	if !configured(d, "realm") || !externalRealm(realm) {
+		return nil
+	}
+
+	var rejected string
+	switch {
+	case configured(d, "password"):
+		rejected = "password can't be set"
+	case len(d.Get("password_generator").([]interface{})) > 0:
+		rejected = "password_generator can't be set"
+	case configured(d, "internal_password_disabled") && !d.Get("internal_password_disabled").(bool):
+		rejected = "internal_password_disabled can't be false"
+	default:
+		return nil
+	}
+
+	offline, err := offlineMode(ctx, m.(*ArtClient))
+	if err != nil {
+		return err
+	} else if offline {
 		return nil
 	}
+	return fmt.Errorf("%s for users of the %s realm unless Artifactory is in offline mode", rejected, realm)
+}
 
-	return err
+type passwordGenerator struct {
+	length  int
+	classes []string
 }
 
-// generatePassword used as default func to generate user passwords
-func generatePassword() string {
-	letters := []byte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
-	b := make([]byte, randomPasswordLength)
-	for i := range b {
-		b[i] = letters[rand.Int63()%int64(len(letters))]
+func unpackPasswordGenerator(d *schema.ResourceData) passwordGenerator {
+	g := passwordGenerator{length: 16}
+	settings := map[string]interface{}{"lower": true, "upper": true, "numeric": true, "special": false}
+	if v, ok := d.GetOk("password_generator"); ok && v.([]interface{})[0] != nil {
+		settings = v.([]interface{})[0].(map[string]interface{})
+		g.length = settings["length"].(int)
+	}
+
+	for _, class := range []struct{ key, chars string }{
+		{"lower", "abcdefghijklmnopqrstuvwxyz"},
+		{"upper", "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{"numeric", "0123456789"},
+		{"special", "!#$%&*+-=?@^_~"},
+	} {
+		if settings[class.key].(bool) {
+			g.classes = append(g.classes, class.chars)
+		}
+	}
+	return g
+}
+
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// generate returns a password with at least one character of every class
+func (g passwordGenerator) generate() (string, error) {
+	if len(g.classes) == 0 {
+		return "", fmt.Errorf("password_generator needs at least one character class")
+	}
+	if g.length < len(g.classes) {
+		return "", fmt.Errorf("password_generator length %d is too short for %d character classes", g.length, len(g.classes))
+	}
+
+	all := ""
+	for _, class := range g.classes {
+		all += class
+	}
+
+	password := make([]byte, g.length)
+	for i := range password {
+		chars := all
+		if i < len(g.classes) {
+			chars = g.classes[i]
+		}
+		j, err := randomIndex(len(chars))
+		if err != nil {
+			return "", err
+		}
+		password[i] = chars[j]
+	}
+
+	// the characters picked from each class would otherwise always lead
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
 	}
-	return string(b)
+	return string(password), nil
 }
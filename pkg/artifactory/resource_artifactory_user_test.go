@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
 )
 
 const userBasic = `
@@ -66,9 +70,184 @@ func TestAccUser_full(t *testing.T) {
 	})
 }
 
+const userPassword = `
+resource "artifactory_user" "foobar" {
+	name             = "dummy_password_user"
+	email            = "dummy_password@a.com"
+	groups           = [ "readers" ]
+	password         = "Passw0rd!-dummy"
+	password_version = %d
+}`
+
+func TestAccUser_passwordVersion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckUserDestroy("artifactory_user.foobar"),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(userPassword, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifactory_user.foobar", "password", secretDigest("dummy_password_user", 1, "Passw0rd!-dummy")),
+					resource.TestCheckResourceAttr("artifactory_user.foobar", "password_version", "1"),
+				),
+			},
+			{
+				Config:             fmt.Sprintf(userPassword, 2),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: fmt.Sprintf(userPassword, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifactory_user.foobar", "password", secretDigest("dummy_password_user", 2, "Passw0rd!-dummy")),
+				),
+			},
+		},
+	})
+}
+
+const userGeneratedPassword = `
+resource "artifactory_user" "foobar" {
+	name   = "dummy_generated_user"
+	email  = "dummy_generated@a.com"
+	groups = [ "readers" ]
+
+	password_generator {
+		length  = 24
+		special = true
+	}
+}`
+
+func TestAccUser_generatedPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckUserDestroy("artifactory_user.foobar"),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: userGeneratedPassword,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("artifactory_user.foobar", "generated_password", regexp.MustCompile(`^.{24}$`)),
+					resource.TestCheckResourceAttr("artifactory_user.foobar", "locked_out", "false"),
+				),
+			},
+		},
+	})
+}
+
+const userExternalRealm = `
+resource "artifactory_user" "foobar" {
+	name   = "dummy_ldap_user"
+	email  = "dummy_ldap@a.com"
+	groups = [ "readers" ]
+	realm  = "ldap"
+	%s
+}
+
+data "artifactory_user" "foobar" {
+	name = artifactory_user.foobar.name
+}`
+
+func TestAccUser_externalRealm(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckUserDestroy("artifactory_user.foobar"),
+		Providers:    testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(userExternalRealm, `password = "Passw0rd!-dummy"`),
+				ExpectError: regexp.MustCompile("password can't be set for users of the ldap realm"),
+			},
+			{
+				Config: fmt.Sprintf(userExternalRealm, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifactory_user.foobar", "internal_password_disabled", "true"),
+					resource.TestCheckResourceAttr("artifactory_user.foobar", "generated_password", ""),
+					resource.TestCheckResourceAttr("data.artifactory_user.foobar", "email", "dummy_ldap@a.com"),
+					resource.TestCheckResourceAttrPair("data.artifactory_user.foobar", "realm", "artifactory_user.foobar", "realm"),
+				),
+			},
+		},
+	})
+}
+
+func TestExternalRealm(t *testing.T) {
+	for realm, external := range map[string]bool{"": false, "internal": false, "ldap": true, "saml": true, "crowd": true} {
+		if externalRealm(realm) != external {
+			t.Errorf("expected externalRealm(%q) to be %t", realm, external)
+		}
+	}
+}
+
+func TestAuthenticatesElsewhere(t *testing.T) {
+	for _, offline := range []bool{false, true} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<config xmlns="http://artifactory.jfrog.org/xsd/2.2.5"><offlineMode>%t</offlineMode></config>`, offline)
+		}))
+
+		rt, err := artifactory.NewClient(server.URL, server.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := &ArtClient{Artifactory: rt}
+
+		for realm, expected := range map[string]bool{"internal": false, "ldap": !offline} {
+			d := schema.TestResourceDataRaw(t, resourceArtifactoryUser().Schema, map[string]interface{}{"name": "jane", "realm": realm})
+			if external, err := authenticatesElsewhere(context.Background(), c, d); err != nil {
+				t.Error(err)
+			} else if external != expected {
+				t.Errorf("expected authenticatesElsewhere of a %s user with offline mode %t to be %t", realm, offline, expected)
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestPasswordGenerator(t *testing.T) {
+	g := passwordGenerator{length: 12, classes: []string{"abc", "XYZ", "123", "!#"}}
+	for i := 0; i < 100; i++ {
+		password, err := g.generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(password) != g.length {
+			t.Fatalf("expected a password of %d characters, got %q", g.length, password)
+		}
+		for _, class := range g.classes {
+			if !strings.ContainsAny(password, class) {
+				t.Fatalf("expected %q to hold one of %q", password, class)
+			}
+		}
+	}
+
+	if _, err := (passwordGenerator{length: 3, classes: g.classes}).generate(); err == nil {
+		t.Error("expected a password shorter than the number of classes to fail")
+	}
+	if _, err := (passwordGenerator{length: 12}).generate(); err == nil {
+		t.Error("expected a generator without classes to fail")
+	}
+}
+
+func TestUser_stateUpgradeV0(t *testing.T) {
+	testStateUpgrade(t, resourceArtifactoryUser(), 0, map[string]interface{}{
+		"id":       "dummy_user",
+		"name":     "dummy_user",
+		"email":    "dummy@a.com",
+		"password": "bGVnYWN5LWhhc2g=",
+	}, map[string]interface{}{
+		"id":               "dummy_user",
+		"name":             "dummy_user",
+		"email":            "dummy@a.com",
+		"password":         "",
+		"password_version": 0,
+	})
+}
+
 func testAccCheckUserDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 
 		if !ok {
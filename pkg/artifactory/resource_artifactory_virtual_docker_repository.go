@@ -0,0 +1,9 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceArtifactoryVirtualDockerRepository() *schema.Resource {
+	return virtualRepositoryResource("docker", nil, nil, nil)
+}
@@ -0,0 +1,22 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryVirtualHelmRepository() *schema.Resource {
+	return virtualRepositoryResource("helm", map[string]*schema.Schema{
+		"virtual_retrieval_cache_period_seconds": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+	}, func(d *ResourceData, repo *v1.VirtualRepository) {
+		repo.VirtualRetrievalCachePeriodSecs = d.getIntRef("virtual_retrieval_cache_period_seconds", true)
+	}, func(repo *v1.VirtualRepository, d *schema.ResourceData, p *packer) {
+		p.set("virtual_retrieval_cache_period_seconds", repo.VirtualRetrievalCachePeriodSecs)
+	})
+}
@@ -0,0 +1,32 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryVirtualMavenRepository() *schema.Resource {
+	return virtualRepositoryResource("maven", map[string]*schema.Schema{
+		"pom_repository_references_cleanup_policy": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "discard_active_reference",
+			ValidateFunc: validation.StringInSlice([]string{
+				"discard_active_reference",
+				"discard_any_reference",
+				"nothing",
+			}, false),
+		},
+		"key_pair": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	}, func(d *ResourceData, repo *v1.VirtualRepository) {
+		repo.PomRepositoryReferencesCleanupPolicy = d.getStringRef("pom_repository_references_cleanup_policy", true)
+		repo.KeyPair = d.getStringRef("key_pair", true)
+	}, func(repo *v1.VirtualRepository, d *schema.ResourceData, p *packer) {
+		p.set("pom_repository_references_cleanup_policy", repo.PomRepositoryReferencesCleanupPolicy)
+		p.set("key_pair", repo.KeyPair)
+	})
+}
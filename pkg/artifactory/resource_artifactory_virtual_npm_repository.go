@@ -0,0 +1,20 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
+)
+
+func resourceArtifactoryVirtualNpmRepository() *schema.Resource {
+	return virtualRepositoryResource("npm", map[string]*schema.Schema{
+		"external_dependencies_enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}, func(d *ResourceData, repo *v1.VirtualRepository) {
+		repo.ExternalDependenciesEnabled = d.getBoolRef("external_dependencies_enabled", true)
+	}, func(repo *v1.VirtualRepository, d *schema.ResourceData, p *packer) {
+		p.set("external_dependencies_enabled", repo.ExternalDependenciesEnabled)
+	})
+}
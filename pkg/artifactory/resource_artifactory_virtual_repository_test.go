@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/rickardl/go-artifactory/v2/artifactory"
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/rickardl/go-artifactory/v2/artifactory/client"
+	v1 "github.com/rickardl/go-artifactory/v2/artifactory/v1"
 )
 
 const virtualRepositoryBasic = `
@@ -73,12 +77,31 @@ func TestAccVirtualRepository_update(t *testing.T) {
 				),
 			},
 			{
+				// Change a setting terraform does not manage behind its back
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*ArtClient)
+					repo := &v1.VirtualRepository{VirtualRetrievalCachePeriodSecs: artifactory.Int(1234)}
+					if _, err := client.V1.Repositories.UpdateVirtual(context.Background(), "foo", repo); err != nil {
+						t.Fatal(err)
+					}
+				},
 				Config: virtualRepositoryUpdateAfter,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifactory_virtual_repository.foo", "key", "foo"),
 					resource.TestCheckResourceAttr("artifactory_virtual_repository.foo", "description", "After"),
 					resource.TestCheckResourceAttr("artifactory_virtual_repository.foo", "package_type", "maven"),
 					resource.TestCheckResourceAttr("artifactory_virtual_repository.foo", "repositories.#", "0"),
+					func(s *terraform.State) error {
+						client := testAccProvider.Meta().(*ArtClient)
+						repo, _, err := client.V1.Repositories.GetVirtual(context.Background(), "foo")
+						if err != nil {
+							return err
+						}
+						if repo.VirtualRetrievalCachePeriodSecs == nil || *repo.VirtualRetrievalCachePeriodSecs != 1234 {
+							return fmt.Errorf("error: update reset virtual_retrieval_cache_period_secs on %s", *repo.Key)
+						}
+						return nil
+					},
 				),
 			},
 		},
@@ -125,9 +148,83 @@ func TestAccVirtualRepository_full(t *testing.T) {
 	})
 }
 
+const virtualRepositoryNested = `
+resource "artifactory_local_repository" "members-local" {
+	key          = "virtual-members-local"
+	package_type = "maven"
+}
+
+resource "artifactory_remote_repository" "members-remote" {
+	key          = "virtual-members-remote"
+	package_type = "maven"
+	url          = "https://repo1.maven.org/maven2/"
+}
+
+resource "artifactory_virtual_repository" "inner" {
+	key                     = "virtual-members-inner"
+	package_type            = "maven"
+	repositories            = ["${artifactory_remote_repository.members-remote.key}", "${artifactory_local_repository.members-local.key}"]
+	default_deployment_repo = "${artifactory_local_repository.members-local.key}"
+}
+
+resource "artifactory_virtual_repository" "outer" {
+	key          = "virtual-members-outer"
+	package_type = "maven"
+	repositories = ["${artifactory_virtual_repository.inner.key}"]
+}
+`
+
+func TestAccVirtualRepository_resolvedRepositories(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckVirtualRepositoryDestroy("artifactory_virtual_repository.outer"),
+		Providers:    testAccProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: virtualRepositoryNested,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifactory_virtual_repository.inner", "resolved_repositories.#", "2"),
+					resource.TestCheckResourceAttr("artifactory_virtual_repository.inner", "resolved_repositories.0", "virtual-members-local"),
+					resource.TestCheckResourceAttr("artifactory_virtual_repository.inner", "resolved_repositories.1", "virtual-members-remote"),
+					resource.TestCheckResourceAttr("artifactory_virtual_repository.outer", "resolved_repositories.#", "2"),
+					resource.TestCheckResourceAttr("artifactory_virtual_repository.outer", "resolved_repositories.0", "virtual-members-local"),
+				),
+			},
+		},
+	})
+}
+
+const virtualRepositoryWrongMemberType = `
+resource "artifactory_local_repository" "npm" {
+	key          = "virtual-members-npm"
+	package_type = "npm"
+}
+
+resource "artifactory_virtual_repository" "foo" {
+	key          = "virtual-members-wrong-type"
+	package_type = "maven"
+	repositories = ["${artifactory_local_repository.npm.key}"]
+}
+`
+
+func TestAccVirtualRepository_memberPackageTypeMismatch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config:      virtualRepositoryWrongMemberType,
+				ExpectError: regexp.MustCompile("repository virtual-members-npm has package type npm, expected maven"),
+			},
+		},
+	})
+}
+
 func testAccCheckVirtualRepositoryDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*artifactory.Artifactory)
+		client := testAccProvider.Meta().(*ArtClient)
 		rs, ok := s.RootModule().Resources[id]
 
 		if !ok {
@@ -144,3 +241,34 @@ func testAccCheckVirtualRepositoryDestroy(id string) func(*terraform.State) erro
 		}
 	}
 }
+
+func TestVirtualMembersCustomizeDiff_packageTypePerRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"key": "libs-local", "type": "LOCAL", "packageType": "Maven"}, {"key": "npm-local", "type": "LOCAL", "packageType": "Npm"}]`)
+	}))
+	defer server.Close()
+
+	raw, err := client.NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ArtClient{Raw: raw}
+
+	// the generic resource is built once, every repository goes through the same diff func
+	r := resourceArtifactoryVirtualRepository()
+	for _, repo := range []struct{ key, packageType, member string }{
+		{"maven-virtual", "maven", "libs-local"},
+		{"npm-virtual", "npm", "npm-local"},
+		{"maven-virtual-2", "maven", "libs-local"},
+	} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"key":          repo.key,
+			"package_type": repo.packageType,
+			"repositories": []interface{}{repo.member},
+		})
+		if _, err := r.Diff(context.Background(), nil, config, c); err != nil {
+			t.Errorf("%s: %s", repo.key, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Resources without a SchemaVersion are at version 0, a resource at version n has an upgrader for each version below n.
+// Upgraders only see the state terraform stored, decoded into plain maps, slices, strings, bools and float64s
+
+// stateUpgrader upgrades states of the given version to version+1 by running upgrades in order. s is a snapshot of
+// the schema the states were written with, not the current one
+func stateUpgrader(version int, s map[string]*schema.Schema, upgrades ...schema.StateUpgradeFunc) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    (&schema.Resource{Schema: s}).CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			for _, upgrade := range upgrades {
+				var err error
+				if rawState, err = upgrade(ctx, rawState, meta); err != nil {
+					return nil, err
+				}
+			}
+			return rawState, nil
+		},
+	}
+}
+
+// addStateDefaults fills in attributes that were added with a default. They only live in the state, so refresh
+// never sets them and the first plan after upgrading would show them as changes
+func addStateDefaults(defaults map[string]interface{}) schema.StateUpgradeFunc {
+	return func(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+		for key, value := range defaults {
+			if rawState[key] == nil {
+				rawState[key] = value
+			}
+		}
+		return rawState, nil
+	}
+}
+
+// upgradeSecretsV0 migrates states from before secrets had a version. Their digests were salted differently and
+// can't be converted, so they are cleared and the secret is sent once more on the next apply
+func upgradeSecretsV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	// replication configs keep a password per replication
+	if _, ok := rawState["replications"]; !ok {
+		clearSecret(rawState, "password")
+		return rawState, nil
+	}
+
+	replications, _ := rawState["replications"].([]interface{})
+	for _, raw := range replications {
+		if replication, ok := raw.(map[string]interface{}); ok {
+			clearSecret(replication, "password")
+		}
+	}
+	return rawState, nil
+}
+
+func clearSecret(state map[string]interface{}, key string) {
+	if _, ok := state[key]; ok {
+		state[key] = ""
+	}
+	state[key+"_version"] = 0
+}
+
+func secretStateUpgraders(v0 map[string]*schema.Schema) []schema.StateUpgrader {
+	return []schema.StateUpgrader{
+		stateUpgrader(0, v0, upgradeSecretsV0),
+	}
+}
+
+func localRepositoryStateUpgraders() []schema.StateUpgrader {
+	return []schema.StateUpgrader{
+		stateUpgrader(0, localRepositorySchemaV0(), addStateDefaults(map[string]interface{}{"force_destroy": false})),
+	}
+}
+
+func remoteRepositoryStateUpgraders() []schema.StateUpgrader {
+	return []schema.StateUpgrader{
+		stateUpgrader(0, remoteRepositorySchemaV0(), upgradeSecretsV0, addStateDefaults(map[string]interface{}{
+			"force_destroy":              false,
+			"verify_connection":          false,
+			"verify_connection_path":     "",
+			"verify_connection_rollback": false,
+		})),
+	}
+}
+
+// Schemas of earlier versions, terraform decodes states of a version with the schema they were written with. Only
+// the types matter, so validation, defaults and the like are left out
+
+// userSchemaV0 is artifactory_user before secrets had a version
+func userSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"admin":                      {Type: schema.TypeBool},
+		"disable_ui_access":          {Type: schema.TypeBool},
+		"email":                      {Type: schema.TypeString},
+		"groups":                     {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+		"internal_password_disabled": {Type: schema.TypeBool},
+		"name":                       {Type: schema.TypeString},
+		"password":                   {Type: schema.TypeString},
+		"profile_updatable":          {Type: schema.TypeBool},
+	}
+}
+
+// replicationConfigSchemaV0 is artifactory_replication_config before secrets had a version
+func replicationConfigSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cron_exp":                 {Type: schema.TypeString},
+		"enable_event_replication": {Type: schema.TypeBool},
+		"replications": {Type: schema.TypeList, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+			"enabled":               {Type: schema.TypeBool},
+			"password":              {Type: schema.TypeString},
+			"path_prefix":           {Type: schema.TypeString},
+			"socket_timeout_millis": {Type: schema.TypeInt},
+			"sync_deletes":          {Type: schema.TypeBool},
+			"sync_properties":       {Type: schema.TypeBool},
+			"sync_statistics":       {Type: schema.TypeBool},
+			"url":                   {Type: schema.TypeString},
+			"username":              {Type: schema.TypeString},
+		}}},
+		"repo_key": {Type: schema.TypeString},
+	}
+}
+
+// singleReplicationConfigSchemaV0 is artifactory_single_replication_config before secrets had a version
+func singleReplicationConfigSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cron_exp":                 {Type: schema.TypeString},
+		"enable_event_replication": {Type: schema.TypeBool},
+		"enabled":                  {Type: schema.TypeBool},
+		"password":                 {Type: schema.TypeString},
+		"path_prefix":              {Type: schema.TypeString},
+		"repo_key":                 {Type: schema.TypeString},
+		"socket_timeout_millis":    {Type: schema.TypeInt},
+		"sync_deletes":             {Type: schema.TypeBool},
+		"sync_properties":          {Type: schema.TypeBool},
+		"sync_statistics":          {Type: schema.TypeBool},
+		"url":                      {Type: schema.TypeString},
+		"username":                 {Type: schema.TypeString},
+	}
+}
+
+// localRepositorySchemaV0 is artifactory_local_repository before force_destroy
+func localRepositorySchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"archive_browsing_enabled":        {Type: schema.TypeBool},
+		"blacked_out":                     {Type: schema.TypeBool},
+		"calculate_yum_metadata":          {Type: schema.TypeBool},
+		"checksum_policy_type":            {Type: schema.TypeString},
+		"debian_trivial_layout":           {Type: schema.TypeBool},
+		"description":                     {Type: schema.TypeString},
+		"docker_api_version":              {Type: schema.TypeString},
+		"enable_file_lists_indexing":      {Type: schema.TypeBool},
+		"excludes_pattern":                {Type: schema.TypeString},
+		"handle_releases":                 {Type: schema.TypeBool},
+		"handle_snapshots":                {Type: schema.TypeBool},
+		"includes_pattern":                {Type: schema.TypeString},
+		"key":                             {Type: schema.TypeString},
+		"max_unique_snapshots":            {Type: schema.TypeInt},
+		"max_unique_tags":                 {Type: schema.TypeInt},
+		"notes":                           {Type: schema.TypeString},
+		"package_type":                    {Type: schema.TypeString},
+		"property_sets":                   {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+		"repo_layout_ref":                 {Type: schema.TypeString},
+		"snapshot_version_behavior":       {Type: schema.TypeString},
+		"suppress_pom_consistency_checks": {Type: schema.TypeBool},
+		"xray_index":                      {Type: schema.TypeBool},
+		"yum_root_depth":                  {Type: schema.TypeInt},
+	}
+}
+
+// remoteRepositorySchemaV0 is artifactory_remote_repository before secrets had a version
+func remoteRepositorySchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"allow_any_host_auth":          {Type: schema.TypeBool},
+		"blacked_out":                  {Type: schema.TypeBool},
+		"block_mismatching_mime_types": {Type: schema.TypeBool},
+		"bower_registry_url":           {Type: schema.TypeString},
+		"bypass_head_requests":         {Type: schema.TypeBool},
+		"client_tls_certificate":       {Type: schema.TypeString},
+		"description":                  {Type: schema.TypeString},
+		"download_context_path":        {Type: schema.TypeString},
+		"enable_cookie_management":     {Type: schema.TypeBool},
+		"enable_token_authentication":  {Type: schema.TypeBool},
+		"excludes_pattern":             {Type: schema.TypeString},
+		"feed_context_path":            {Type: schema.TypeString},
+		"fetch_jars_eagerly":           {Type: schema.TypeBool},
+		"fetch_sources_eagerly":        {Type: schema.TypeBool},
+		"handle_releases":              {Type: schema.TypeBool},
+		"handle_snapshots":             {Type: schema.TypeBool},
+		"hard_fail":                    {Type: schema.TypeBool},
+		"includes_pattern":             {Type: schema.TypeString},
+		"key":                          {Type: schema.TypeString},
+		"local_address":                {Type: schema.TypeString},
+		"max_unique_snapshots":         {Type: schema.TypeInt},
+		"missed_cache_period_seconds":  {Type: schema.TypeInt},
+		"notes":                        {Type: schema.TypeString},
+		"nuget": {Type: schema.TypeList, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+			"download_context_path": {Type: schema.TypeString},
+			"feed_context_path":     {Type: schema.TypeString},
+			"v3_feed_url":           {Type: schema.TypeString},
+		}}},
+		"offline":                               {Type: schema.TypeBool},
+		"package_type":                          {Type: schema.TypeString},
+		"password":                              {Type: schema.TypeString},
+		"property_sets":                         {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}},
+		"proxy":                                 {Type: schema.TypeString},
+		"pypi_registry_url":                     {Type: schema.TypeString},
+		"remote_repo_checksum_policy_type":      {Type: schema.TypeString},
+		"repo_layout_ref":                       {Type: schema.TypeString},
+		"retrieval_cache_period_seconds":        {Type: schema.TypeInt},
+		"share_configuration":                   {Type: schema.TypeBool},
+		"socket_timeout_millis":                 {Type: schema.TypeInt},
+		"store_artifacts_locally":               {Type: schema.TypeBool},
+		"suppress_pom_consistency_checks":       {Type: schema.TypeBool},
+		"synchronize_properties":                {Type: schema.TypeBool},
+		"unused_artifacts_cleanup_period_hours": {Type: schema.TypeInt},
+		"url":                                   {Type: schema.TypeString},
+		"username":                              {Type: schema.TypeString},
+		"v3_feed_url":                           {Type: schema.TypeString},
+		"vcs_git_download_url":                  {Type: schema.TypeString},
+		"vcs_git_provider":                      {Type: schema.TypeString},
+		"vcs_type":                              {Type: schema.TypeString},
+		"xray_index":                            {Type: schema.TypeBool},
+	}
+}
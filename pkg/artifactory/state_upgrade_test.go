@@ -0,0 +1,136 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testStateUpgrade runs the upgraders of r on a state of the given version the way terraform does: the state is
+// decoded from json and every version up to the current one needs an upgrader, whose type has to decode the state of
+// its version. The result has to decode with the current schema and match expected
+func testStateUpgrade(t *testing.T, r *schema.Resource, version int, state, expected map[string]interface{}) {
+	t.Helper()
+
+	raw := upgradeState(t, r, version, state)
+
+	// upgraders may set ints where json has float64s, compare what terraform would store
+	if actual, expected := jsonRoundTrip(t, raw), jsonRoundTrip(t, expected); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected upgraded state\n%v\ngot\n%v", expected, actual)
+	}
+}
+
+// testUpgradedPlan upgrades a state of the given version like testStateUpgrade and plans config against it. The
+// attributes starting with any of keys must not change
+func testUpgradedPlan(t *testing.T, r *schema.Resource, version int, state, config map[string]interface{}, keys ...string) {
+	t.Helper()
+
+	js, err := json.Marshal(upgradeState(t, r, version, state))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := ctyjson.Unmarshal(js, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := r.ShimInstanceStateFromValue(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := r.Diff(context.Background(), is, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("failed to plan the upgraded state: %s", err)
+	}
+	if diff == nil {
+		return
+	}
+	for attr, d := range diff.Attributes {
+		for _, key := range keys {
+			if strings.HasPrefix(attr, key) {
+				t.Errorf("expected no change of %s after upgrading, got %q => %q", attr, d.Old, d.New)
+			}
+		}
+	}
+}
+
+// upgradeState runs the upgraders of r from version on and checks the result decodes with the current schema
+func upgradeState(t *testing.T, r *schema.Resource, version int, state map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	raw := jsonRoundTrip(t, state)
+	for ; version < r.SchemaVersion; version++ {
+		upgrader, ok := findStateUpgrader(r, version)
+		if !ok {
+			t.Fatalf("no state upgrader for version %d", version)
+		}
+
+		js, err := json.Marshal(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ctyjson.Unmarshal(js, upgrader.Type); err != nil {
+			t.Fatalf("state of version %d does not match the schema of its upgrader: %s", version, err)
+		}
+
+		if raw, err = upgrader.Upgrade(context.Background(), raw, nil); err != nil {
+			t.Fatalf("failed to upgrade state from version %d: %s", version, err)
+		}
+	}
+
+	js, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctyjson.Unmarshal(js, r.CoreConfigSchema().ImpliedType()); err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %s", err)
+	}
+	return raw
+}
+
+func findStateUpgrader(r *schema.Resource, version int) (schema.StateUpgrader, bool) {
+	for _, upgrader := range r.StateUpgraders {
+		if upgrader.Version == version {
+			return upgrader, true
+		}
+	}
+	return schema.StateUpgrader{}, false
+}
+
+func jsonRoundTrip(t *testing.T, state map[string]interface{}) map[string]interface{} {
+	js, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(js, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestProvider_stateUpgraders(t *testing.T) {
+	for name, r := range Provider().ResourcesMap {
+		if len(r.StateUpgraders) != r.SchemaVersion {
+			t.Errorf("%s: expected %d state upgraders for schema version %d, got %d", name, r.SchemaVersion, r.SchemaVersion, len(r.StateUpgraders))
+			continue
+		}
+
+		for i, upgrader := range r.StateUpgraders {
+			if upgrader.Version != i {
+				t.Errorf("%s: expected state upgrader %d to handle version %d, got %d", name, i, i, upgrader.Version)
+			}
+			if upgrader.Type == cty.NilType || upgrader.Upgrade == nil {
+				t.Errorf("%s: state upgrader for version %d needs a type and an upgrade function", name, upgrader.Version)
+			}
+		}
+	}
+}
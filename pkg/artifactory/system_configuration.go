@@ -0,0 +1,77 @@
+package artifactory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+// systemConfiguration is the part of the config descriptor, artifactory.config.xml, the provider manages
+type systemConfiguration struct {
+	XMLName     xml.Name `xml:"config"`
+	OfflineMode bool     `xml:"offlineMode"`
+	Security    struct {
+		LdapSettings      []ldapSetting      `xml:"ldapSettings>ldapSetting"`
+		LdapGroupSettings []ldapGroupSetting `xml:"ldapGroupSettings>ldapGroupSetting"`
+	} `xml:"security"`
+}
+
+func parseSystemConfiguration(config string) (*systemConfiguration, error) {
+	parsed := new(systemConfiguration)
+	if err := xml.Unmarshal([]byte(config), parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse the system configuration: %s", err)
+	}
+	return parsed, nil
+}
+
+func getSystemConfiguration(ctx context.Context, c *ArtClient) (*systemConfiguration, error) {
+	config, _, err := c.V1.System.GetConfiguration(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the system configuration: %s", err)
+	}
+	return parseSystemConfiguration(*config)
+}
+
+// offlineMode tells whether artifactory is in offline mode, it makes no outbound connections then
+func offlineMode(ctx context.Context, c *ArtClient) (bool, error) {
+	config, err := getSystemConfiguration(ctx, c)
+	if err != nil {
+		return false, err
+	}
+	return config.OfflineMode, nil
+}
+
+// patchSystemConfiguration merges patch into the config descriptor. The api takes yaml, json is sent as it is valid
+// yaml. A nil value removes the key
+func patchSystemConfiguration(ctx context.Context, c *ArtClient, patch interface{}) error {
+	systemConfigLock.Lock()
+	defer systemConfigLock.Unlock()
+
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.Raw.NewRequest(http.MethodPatch, "/api/system/configuration", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+
+	if _, err := c.Raw.Do(ctx, req, new(bytes.Buffer)); err != nil {
+		return fmt.Errorf("failed to update the system configuration: %s", err)
+	}
+	return nil
+}
+
+// securityPatch builds the patch of a single entry of a security section of the config descriptor
+func securityPatch(section, key string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"security": map[string]interface{}{
+			section: map[string]interface{}{key: value},
+		},
+	}
+}
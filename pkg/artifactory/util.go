@@ -1,33 +1,64 @@
 package artifactory
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/hashicorp/terraform/helper/hashcode"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/rickardl/go-artifactory/v2/artifactory"
 )
 
 type ResourceData struct{ *schema.ResourceData }
 
 func (d *ResourceData) getStringRef(key string, onlyIfChanged bool) *string {
-	if v, ok := d.GetOk(key); ok && (!onlyIfChanged || d.HasChange(key)) {
+	if onlyIfChanged {
+		// A value cleared in the config is a change as well and has to reach the server as an empty string
+		if d.HasChange(key) {
+			return artifactory.String(d.Get(key).(string))
+		}
+		return nil
+	}
+
+	if v, ok := d.GetOk(key); ok {
 		return artifactory.String(v.(string))
 	}
 	return nil
 }
 
 func (d *ResourceData) getBoolRef(key string, onlyIfChanged bool) *bool {
-	if v, ok := d.GetOkExists(key); ok && (!onlyIfChanged || d.HasChange(key)) {
+	if onlyIfChanged {
+		// like strings, a value removed from the config is sent as false
+		if d.HasChange(key) {
+			return artifactory.Bool(d.Get(key).(bool))
+		}
+		return nil
+	}
+
+	if v, ok := d.GetOkExists(key); ok {
 		return artifactory.Bool(v.(bool))
 	}
 	return nil
 }
 
 func (d *ResourceData) getIntRef(key string, onlyIfChanged bool) *int {
-	if v, ok := d.GetOkExists(key); ok && (!onlyIfChanged || d.HasChange(key)) {
+	if onlyIfChanged {
+		// like strings, a value removed from the config is sent as 0
+		if d.HasChange(key) {
+			return artifactory.Int(d.Get(key).(int))
+		}
+		return nil
+	}
+
+	if v, ok := d.GetOkExists(key); ok {
 		return artifactory.Int(v.(int))
 	}
 	return nil
@@ -67,30 +98,236 @@ func castToInterfaceArr(arr []string) []interface{} {
 	return cpy
 }
 
-func getMD5Hash(o interface{}) string {
-	if len(o.(string)) == 0 { // Don't hash empty strings
+// secretSchema is the schema of a write-only secret. Artifactory never hands secrets back, so the state only keeps
+// a digest of the configured value and the secret is sent when that value or the <key>_version next to it changes
+func secretSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Sensitive:        true,
+		DiffSuppressFunc: suppressSecretDiff,
+	}
+}
+
+// secretVersionSchema is the rotation trigger of a secret. Bumping it sends the configured secret again, e.g. after
+// it was changed in the UI
+func secretVersionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeInt,
+		Optional: true,
+		Default:  0,
+	}
+}
+
+// secretDigest is what the state keeps of a secret. It is salted with the resource id and the version, so equal
+// secrets don't show up as equal digests across resources and a new version never matches the old digest
+func secretDigest(id string, version int, secret string) string {
+	if len(secret) == 0 {
 		return ""
 	}
 
-	hasher := sha256.New()
-	hasher.Write([]byte(o.(string)))
-	hasher.Write([]byte("OQ9@#9i4$c8g$4^n%PKT8hUva3CC^5"))
-	return hex.EncodeToString(hasher.Sum(nil))
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%s", id, version, secret)))
+	return hex.EncodeToString(sum[:])
 }
 
-func cascadingErr(hasErr *bool) func(error) {
-	if hasErr == nil {
-		panic("hasError cannot be nil")
+func suppressSecretDiff(k, old, new string, d *schema.ResourceData) bool {
+	return old == secretDigest(d.Id(), d.Get(k+"_version").(int), new)
+}
+
+// getSecretRef returns the secret only when it has to be sent, i.e. its value or its version changed
+func (d *ResourceData) getSecretRef(key string) *string {
+	if d.HasChange(key) {
+		return artifactory.String(d.Get(key).(string))
 	}
-	return func(err error) {
-		if err != nil {
-			fmt.Println(err)
-			*hasErr = true
+	return nil
+}
+
+// getConfiguredSecretRef returns a secret as configured, whether it changed or not. It is for apis replacing the whole
+// object, which would drop a secret that isn't sent. The state only holds the digest, so the secret is taken from
+// the config. Path steps are attribute names and list indexes
+func (d *ResourceData) getConfiguredSecretRef(path ...interface{}) *string {
+	v := d.GetRawConfig()
+	for _, step := range path {
+		if v.IsNull() || !v.IsKnown() {
+			return nil
+		}
+		switch step := step.(type) {
+		case string:
+			v = v.GetAttr(step)
+		case int:
+			if v.LengthInt() <= step {
+				return nil
+			}
+			v = v.Index(cty.NumberIntVal(int64(step)))
 		}
 	}
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	return artifactory.String(v.AsString())
+}
+
+// stateSecretDigest returns the value to keep in the state for a secret: the digest of the new value while it is
+// applied, the digest already in the state otherwise
+func stateSecretDigest(d *schema.ResourceData, key string) string {
+	if d.HasChange(key) {
+		return secretDigest(d.Id(), d.Get(key+"_version").(int), d.Get(key).(string))
+	}
+	return d.Get(key).(string)
+}
+
+// packer sets the attributes of a resource read from artifactory. Failures don't stop packing, they are logged
+// and collected together with the attribute they belong to
+type packer struct {
+	d            *schema.ResourceData
+	resourceType string
+	errs         []string
+}
+
+func newPacker(resourceType string, d *schema.ResourceData) *packer {
+	return &packer{d: d, resourceType: resourceType}
+}
+
+func (p *packer) set(key string, value interface{}) {
+	p.fail(key, p.d.Set(key, value))
+}
+
+// fail records an error packing key, nil errors are ignored
+func (p *packer) fail(key string, err error) {
+	if err == nil {
+		return
+	}
+	log.Printf("[ERROR] %s %s: failed to set %s: %s", p.resourceType, p.d.Id(), key, err)
+	p.errs = append(p.errs, fmt.Sprintf("%s: %s", key, err))
+}
+
+// err combines every recorded failure into one error, nil if there were none
+func (p *packer) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to pack %s %s:\n%s", p.resourceType, p.d.Id(), strings.Join(p.errs, "\n"))
+}
+
+// attributePath turns a key as used with ResourceData, e.g. replications.0.password, into the path of a diagnostic
+func attributePath(key string) cty.Path {
+	var path cty.Path
+	for _, step := range strings.Split(key, ".") {
+		if i, err := strconv.Atoi(step); err == nil {
+			path = path.IndexInt(i)
+		} else {
+			path = path.GetAttr(step)
+		}
+	}
+	return path
+}
+
+// attributeDiag reports an error caused by a single attribute, so terraform can point at it in the config
+func attributeDiag(key string, err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       err.Error(),
+		AttributePath: attributePath(key),
+	}}
 }
 
 func hashPrincipal(o interface{}) int {
 	p := o.(map[string]interface{})
-	return hashcode.String(p["name"].(string)) + 31*hashcode.String(hashcode.Strings(castToStringArr(p["permissions"].(*schema.Set).List())))
+	permissions := castToStringArr(p["permissions"].(*schema.Set).List())
+	return schema.HashString(p["name"].(string)) + 31*schema.HashString(strings.Join(permissions, "-"))
+}
+
+type storageFolderInfo struct {
+	Children []struct {
+		Uri    string `json:"uri"`
+		Folder bool   `json:"folder"`
+	} `json:"children"`
+}
+
+// repositoryHasArtifacts lists the root folder of a repository through the storage api. Metadata folders
+// artifactory creates itself (.npm, .pypi, ...) are not counted as content
+func repositoryHasArtifacts(ctx context.Context, c *ArtClient, key string) (bool, error) {
+	req, err := c.Raw.NewRequest(http.MethodGet, fmt.Sprintf("/api/storage/%s/", key), nil)
+	if err != nil {
+		return false, err
+	}
+
+	info := new(storageFolderInfo)
+	resp, err := c.Raw.Do(ctx, req, info)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, child := range info.Children {
+		if !strings.HasPrefix(strings.TrimPrefix(child.Uri, "/"), ".") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// repositoryNotFound tells whether a repository lookup failed because the repository is gone. Artifactory answers 400
+// rather than 404 for some repository types
+func repositoryNotFound(resp *http.Response) bool {
+	return resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest)
 }
+
+// checkRepositoryDestroy refuses to delete a repository that still holds artifacts, unless force_destroy is set
+func checkRepositoryDestroy(ctx context.Context, c *ArtClient, d *schema.ResourceData, storageKey string) diag.Diagnostics {
+	if d.Get("force_destroy").(bool) {
+		return nil
+	}
+
+	hasArtifacts, err := repositoryHasArtifacts(ctx, c, storageKey)
+	if err != nil {
+		return diag.Errorf("failed to check content of repository %s: %s", storageKey, err)
+	}
+
+	if hasArtifacts {
+		return attributeDiag("force_destroy", fmt.Errorf("repository %s still contains artifacts. Set force_destroy = true to delete it along with its content", d.Id()))
+	}
+	return nil
+}
+
+// keyedMutex serializes read-modify-write cycles on a single server side object, keyed by its name
+type keyedMutex struct {
+	lock  sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: make(map[string]*sync.Mutex)}
+}
+
+func (m *keyedMutex) Lock(key string) {
+	m.lock.Lock()
+	l, ok := m.locks[key]
+	if !ok {
+		l = new(sync.Mutex)
+		m.locks[key] = l
+	}
+	m.lock.Unlock()
+
+	l.Lock()
+}
+
+func (m *keyedMutex) Unlock(key string) {
+	m.lock.Lock()
+	l := m.locks[key]
+	m.lock.Unlock()
+
+	l.Unlock()
+}
+
+// The locks are shared by every provider instance, aliases may point at the same server. systemConfigLock guards the
+// config descriptor, which is patched as a whole
+var (
+	permissionTargetLocks = newKeyedMutex()
+	groupLocks            = newKeyedMutex()
+	systemConfigLock      sync.Mutex
+)
@@ -0,0 +1,58 @@
+package artifactory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacker_err(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"name":  {Type: schema.TypeString, Optional: true},
+		"count": {Type: schema.TypeInt, Optional: true},
+		"tags":  {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+	}, map[string]interface{}{})
+	d.SetId("foo")
+
+	p := newPacker("artifactory_test", d)
+	p.set("name", "foo")
+	assert.NoError(t, p.err())
+
+	p.set("count", "not a number")
+	p.set("tags", 42)
+	err := p.err()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "failed to pack artifactory_test foo")
+		assert.Contains(t, err.Error(), "\ncount: ")
+		assert.Contains(t, err.Error(), "\ntags: ")
+	}
+	assert.Equal(t, "foo", d.Get("name"))
+}
+
+func TestResourceData_refsOnlyIfChanged(t *testing.T) {
+	s := schema.InternalMap{
+		"name":    {Type: schema.TypeString, Optional: true},
+		"enabled": {Type: schema.TypeBool, Optional: true},
+		"count":   {Type: schema.TypeInt, Optional: true},
+		"kept":    {Type: schema.TypeInt, Optional: true},
+	}
+	state := &terraform.InstanceState{ID: "foo", Attributes: map[string]string{
+		"id": "foo", "name": "foo", "enabled": "true", "count": "5", "kept": "3",
+	}}
+
+	// everything but kept is removed from the config, the zero values have to be sent
+	diff, err := s.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{"kept": 3}), nil, nil, true)
+	assert.NoError(t, err)
+	data, err := s.Data(state, diff)
+	assert.NoError(t, err)
+	d := &ResourceData{data}
+
+	assert.Equal(t, "", *d.getStringRef("name", true))
+	assert.Equal(t, false, *d.getBoolRef("enabled", true))
+	assert.Equal(t, 0, *d.getIntRef("count", true))
+	assert.Nil(t, d.getIntRef("kept", true))
+	assert.Equal(t, 3, *d.getIntRef("kept", false))
+}